@@ -0,0 +1,190 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "bytes"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "fmt"
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
+    "io"
+)
+
+// 信任阶段的认证器, 连接建立后由双方在信任阶段调用
+type Authenticator interface {
+    // 主动连接的客户端调用, 向对端证明自己的身份, 返回对端的身份
+    Authenticate(rw io.ReadWriter) (principal string, err error)
+    // 服务端的客户端调用, 验证对端的身份, 返回对端的身份
+    Verify(rw io.ReadWriter) (principal string, err error)
+}
+
+// ---------------- 信任消息 ----------------
+
+// 双方交换相同的信任消息, msg 为 nil 时使用 config.DefaultTrustMsg
+//
+// 信任消息可以被重放, 只用于区分是否为 ztcp 连接, 不能作为认证使用
+func NewTrustMsgAuthenticator(msg []byte) Authenticator {
+    return &trustMsgAuthenticator{msg: msg}
+}
+
+type trustMsgAuthenticator struct {
+    msg []byte
+}
+
+func (m *trustMsgAuthenticator) trustMsg() []byte {
+    if m.msg == nil {
+        return config.DefaultTrustMsg
+    }
+    return m.msg
+}
+
+func (m *trustMsgAuthenticator) send(w io.Writer) error {
+    _, err := w.Write(m.trustMsg())
+    return err
+}
+
+func (m *trustMsgAuthenticator) wait(r io.Reader) error {
+    buff, err := utils.WaitConnData(r, len(m.trustMsg()))
+    if err != nil {
+        return err
+    }
+    if !bytes.Equal(buff, m.trustMsg()) {
+        return fmt.Errorf("%w: 信任消息错误", ErrAuthFailed)
+    }
+    return nil
+}
+
+func (m *trustMsgAuthenticator) Authenticate(rw io.ReadWriter) (string, error) {
+    if err := m.send(rw); err != nil {
+        return "", err
+    }
+    return "", m.wait(rw)
+}
+
+func (m *trustMsgAuthenticator) Verify(rw io.ReadWriter) (string, error) {
+    if err := m.wait(rw); err != nil {
+        return "", err
+    }
+    return "", m.send(rw)
+}
+
+// ---------------- hmac 挑战响应 ----------------
+
+// 随机数长度
+const hmacNonceLength = 32
+
+// 使用共享密钥的 hmac-sha256 双向挑战响应认证, identity 为主动连接方声明的身份
+//
+// 客户端发送随机数和身份, 服务端返回自己的随机数和证明, 客户端验证后再返回自己的证明,
+// 每次握手的随机数都不同, 所以无法重放. 服务端得到的身份为客户端声明的 identity
+func NewHMACAuthenticator(secret []byte, identity string) Authenticator {
+    return &hmacAuthenticator{secret: secret, identity: identity}
+}
+
+type hmacAuthenticator struct {
+    secret   []byte
+    identity string
+}
+
+func (m *hmacAuthenticator) sign(role string, nonces ...[]byte) []byte {
+    mac := hmac.New(sha256.New, m.secret)
+    mac.Write([]byte(role))
+    for _, nonce := range nonces {
+        mac.Write(nonce)
+    }
+    return mac.Sum(nil)
+}
+
+func (m *hmacAuthenticator) Authenticate(rw io.ReadWriter) (string, error) {
+    clientNonce := make([]byte, hmacNonceLength)
+    if _, err := rand.Read(clientNonce); err != nil {
+        return "", err
+    }
+    if err := utils.WriteHandshakeMsg(rw, append(clientNonce, m.identity...)); err != nil {
+        return "", err
+    }
+
+    msg, err := utils.ReadHandshakeMsg(rw)
+    if err != nil {
+        return "", err
+    }
+    if len(msg) != hmacNonceLength+sha256.Size {
+        return "", fmt.Errorf("%w: hmac 握手消息错误", utils.ErrInvalidHandshake)
+    }
+    serverNonce, serverProof := msg[:hmacNonceLength], msg[hmacNonceLength:]
+    if !hmac.Equal(serverProof, m.sign("server", clientNonce, serverNonce, []byte(m.identity))) {
+        return "", fmt.Errorf("%w: 服务端 hmac 验证失败", ErrAuthFailed)
+    }
+
+    return "", utils.WriteHandshakeMsg(rw, m.sign("client", serverNonce, clientNonce, []byte(m.identity)))
+}
+
+func (m *hmacAuthenticator) Verify(rw io.ReadWriter) (string, error) {
+    msg, err := utils.ReadHandshakeMsg(rw)
+    if err != nil {
+        return "", err
+    }
+    if len(msg) < hmacNonceLength {
+        return "", fmt.Errorf("%w: hmac 握手消息错误", utils.ErrInvalidHandshake)
+    }
+    clientNonce, identity := msg[:hmacNonceLength], msg[hmacNonceLength:]
+
+    serverNonce := make([]byte, hmacNonceLength)
+    if _, err := rand.Read(serverNonce); err != nil {
+        return "", err
+    }
+    serverProof := m.sign("server", clientNonce, serverNonce, identity)
+    if err := utils.WriteHandshakeMsg(rw, append(serverNonce, serverProof...)); err != nil {
+        return "", err
+    }
+
+    clientProof, err := utils.ReadHandshakeMsg(rw)
+    if err != nil {
+        return "", err
+    }
+    if !hmac.Equal(clientProof, m.sign("client", serverNonce, clientNonce, identity)) {
+        return "", fmt.Errorf("%w: 客户端 hmac 验证失败", ErrAuthFailed)
+    }
+    return string(identity), nil
+}
+
+// ---------------- token ----------------
+
+// 验证 token, 返回 token 对应的身份
+type TokenVerify func(token string) (principal string, err error)
+
+// token 认证, 主动连接的客户端发送 token, 服务端使用 verify 验证
+//
+// token 是明文传输的, 应该和 tls 一起使用
+func NewTokenAuthenticator(token string, verify TokenVerify) Authenticator {
+    return &tokenAuthenticator{token: token, verify: verify}
+}
+
+type tokenAuthenticator struct {
+    token  string
+    verify TokenVerify
+}
+
+func (m *tokenAuthenticator) Authenticate(rw io.ReadWriter) (string, error) {
+    return "", utils.WriteHandshakeMsg(rw, []byte(m.token))
+}
+
+func (m *tokenAuthenticator) Verify(rw io.ReadWriter) (string, error) {
+    token, err := utils.ReadHandshakeMsg(rw)
+    if err != nil {
+        return "", err
+    }
+    if m.verify == nil {
+        return "", fmt.Errorf("%w: 未设置 TokenVerify", ErrAuthFailed)
+    }
+    return m.verify(string(token))
+}
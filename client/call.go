@@ -0,0 +1,160 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "context"
+    "errors"
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
+    "sync/atomic"
+)
+
+// 对端处理请求时返回的错误
+//
+// 对端正在优雅关闭或未设置 CallHandler 时可以用 errors.Is(err, ErrShuttingDown) 或 errors.Is(err, ErrNoCallHandler) 判断
+type CallError struct {
+    Code config.CallErrorCode
+    Msg  string
+}
+
+func (m CallError) Error() string {
+    return m.Msg
+}
+
+func (m CallError) Is(target error) bool {
+    switch m.Code {
+    case config.CallErrorShuttingDown:
+        return target == ErrShuttingDown
+    case config.CallErrorNoHandler:
+        return target == ErrNoCallHandler
+    }
+    return false
+}
+
+type callResult struct {
+    data []byte
+    err  error
+}
+
+// 发送一个请求并等待对端响应, ctx 可以用于超时和取消
+//
+// 如果 ctx 没有设置截止时间, 会使用 CallTimeout 作为超时时间
+func (m *Client) Call(ctx context.Context, data []byte) ([]byte, error) {
+    if err := m.checkConnected(); err != nil {
+        return nil, err
+    }
+    if m.isPeerGoodbye() {
+        return nil, ErrPeerClosing
+    }
+
+    if _, ok := ctx.Deadline(); !ok && m.opts.CallTimeout > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, m.opts.CallTimeout)
+        defer cancel()
+    }
+
+    requestId := atomic.AddUint64(&m.callSeq, 1)
+    result := make(chan *callResult, 1)
+
+    m.callMx.Lock()
+    m.calls[requestId] = result
+    m.callMx.Unlock()
+
+    defer func() {
+        m.callMx.Lock()
+        delete(m.calls, requestId)
+        m.callMx.Unlock()
+    }()
+
+    err := m.interceptSend(config.FrameRequest, data, func(data []byte) error {
+        return m.writeFrame(config.FrameRequest, utils.Uint64ToBytes(requestId), data)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    select {
+    case r := <-result:
+        return r.data, r.err
+    case <-ctx.Done():
+        return nil, ctx.Err()
+    }
+}
+
+// 处理对端发来的请求, CallHandler 在接收拦截器的最后一个 next 中执行
+//
+// 拦截器在 next 之后可以看到 CallHandler 的错误, 拦截器返回的错误会作为错误响应发给对端, 拦截器丢弃请求时不响应
+func (m *Client) handleRequest(requestId uint64, data []byte) {
+    var handled bool
+    var resp []byte
+    err := m.interceptReceive(config.FrameRequest, data, func(data []byte) error {
+        handled = true
+        if m.opts.CallHandler == nil {
+            return ErrNoCallHandler
+        }
+
+        var err error
+        resp, err = m.opts.CallHandler(m, data)
+        return err
+    })
+    if err != nil {
+        _ = m.writeErrorResponse(requestId, err)
+        return
+    }
+    if !handled {
+        return
+    }
+
+    ext := utils.Uint64ToBytes(requestId)
+    _ = m.interceptSend(config.FrameResponse, resp, func(data []byte) error {
+        return m.writeFrame(config.FrameResponse, ext, data)
+    })
+}
+
+// 发送错误响应, 错误码让对端可以还原出 ErrShuttingDown 等错误
+func (m *Client) writeErrorResponse(requestId uint64, err error) error {
+    code := config.CallErrorHandler
+    switch {
+    case errors.Is(err, ErrShuttingDown):
+        code = config.CallErrorShuttingDown
+    case errors.Is(err, ErrNoCallHandler):
+        code = config.CallErrorNoHandler
+    }
+    ext := utils.ErrorResponseExt(requestId, code)
+    return m.interceptSend(config.FrameErrorResponse, []byte(err.Error()), func(data []byte) error {
+        return m.writeFrame(config.FrameErrorResponse, ext, data)
+    })
+}
+
+// 处理对端发来的响应
+func (m *Client) handleResponse(requestId uint64, result *callResult) {
+    m.callMx.Lock()
+    ch, ok := m.calls[requestId]
+    delete(m.calls, requestId)
+    m.callMx.Unlock()
+
+    // 请求已超时或已取消
+    if !ok {
+        return
+    }
+    ch <- result
+}
+
+// 连接断开时让所有等待中的请求失败
+func (m *Client) failCalls(err error) {
+    m.callMx.Lock()
+    calls := m.calls
+    m.calls = make(map[uint64]chan *callResult)
+    m.callMx.Unlock()
+
+    for _, ch := range calls {
+        ch <- &callResult{err: err}
+    }
+}
@@ -9,9 +9,9 @@
 package client
 
 import (
-    "bytes"
-    "errors"
-    "github.com/zlyuancn/zassert"
+    "bufio"
+    "context"
+    "fmt"
     "github.com/zlyuancn/ztcp/config"
     "github.com/zlyuancn/ztcp/utils"
     "net"
@@ -22,42 +22,84 @@ import (
 
 type Client struct {
     clientId      uint64
+    principal     string
     status        config.ClientStatus
     opts          *Options
     mx            sync.Mutex
     heartbeatTime *utils.HeartbeatTime
+    writer        utils.FrameWriter
+    sendQueue     *sendQueue
+    // 信任阶段协商的压缩器, 为 nil 表示不压缩, 由 mx 保护
+    compressor utils.Compressor
+
+    // 保护 opts.Conn, sendQueue, clientId 和 principal, 重连时会替换
+    connMx sync.RWMutex
+    // 调用 Close 后关闭, 用于中断重连
+    closeChan chan struct{}
+    closeOnce sync.Once
+    // 关闭观察者执行完毕后关闭
+    closedChan chan struct{}
+    // 关闭原因, closedChan 关闭后可读
+    closeReason *CloseReason
+    // 连接成功并且连接观察者执行完毕后关闭, 断开后重置
+    connectedMx   sync.Mutex
+    connectedChan chan struct{}
+
+    // 客户端关闭时取消
+    ctx    context.Context
+    cancel context.CancelFunc
+    // 会话数据
+    session *Session
+
+    // 进行中的数据处理和写入
+    workMx    sync.Mutex
+    workCount int
+    workIdle  chan struct{}
+    // 正在优雅关闭, 不再处理对端发来的新数据
+    draining int32
+    // 对端发来了告别帧
+    peerGoodbye int32
+    // 对端发来了踢出帧
+    peerKicked int32
+    // 往返时间统计
+    rttMx sync.Mutex
+    rtt   RTTStats
+
+    // 本地主动断开连接的原因, 比如心跳超时
+    causeMx sync.Mutex
+    cause   error
+
+    // 等待响应的请求
+    callMx  sync.Mutex
+    calls   map[uint64]chan *callResult
+    callSeq uint64
 }
 
 func NewClient(opts ...Option) (*Client, error) {
     options := newOptions(opts...)
 
     c := &Client{
-        opts:   options,
-        status: config.ClientConnecting,
+        opts:          options,
+        status:        config.ClientConnecting,
+        closeChan:     make(chan struct{}),
+        closedChan:    make(chan struct{}),
+        connectedChan: make(chan struct{}),
+        calls:         make(map[uint64]chan *callResult),
+        session:       newSession(),
     }
+    c.ctx, c.cancel = context.WithCancel(context.Background())
 
     if options.IsServerClient {
-        c.connectedHandler()
+        _, err := c.connectedHandler(false)
+        c.closedHandler(err)
         return c, nil
     }
 
     if options.ConnectAddr == "" {
-        return nil, zassert.AssertError{"未设置 ConnectAddr ,请使用 WithConnectAddr(addr)"}
+        return nil, ErrNoConnectAddr
     }
 
-    go func(m *Client) {
-        var d net.Dialer
-        d.LocalAddr = &net.TCPAddr{Port: m.opts.BindPort}
-
-        conn, err := d.DialContext(m.opts.ConnectContext, "tcp", m.opts.ConnectAddr)
-        if err != nil {
-            m.closedHandler(err)
-            return
-        }
-        m.opts.Conn = conn
-        m.connectedHandler()
-    }(c)
-
+    go c.dialLoop()
     return c, nil
 }
 
@@ -66,17 +108,26 @@ func (m *Client) Options() *Options {
 }
 
 func (m *Client) RemoteAddr() net.Addr {
-    return m.opts.Conn.RemoteAddr()
+    return m.conn().RemoteAddr()
 }
 
 func (m *Client) LocalAddr() net.Addr {
-    return m.opts.Conn.LocalAddr()
+    return m.conn().LocalAddr()
 }
 
 func (m *Client) GetId() uint64 {
+    m.connMx.RLock()
+    defer m.connMx.RUnlock()
     return m.clientId
 }
 
+// 获取信任阶段认证得到的对端身份, 由 Authenticator 决定
+func (m *Client) Principal() string {
+    m.connMx.RLock()
+    defer m.connMx.RUnlock()
+    return m.principal
+}
+
 func (m *Client) Status() config.ClientStatus {
     return config.ClientStatus(atomic.LoadInt32((*int32)(&m.status)))
 }
@@ -89,152 +140,539 @@ func (m *Client) IsConnected() bool {
     return m.Status() == config.ClientConnected
 }
 
+// 关闭客户端, 服务端的客户端会先向对端发送踢出帧, 对端的关闭原因为 ClosePeerKicked
 func (m *Client) Close() error {
-    if m.opts.Conn == nil {
+    var first bool
+    m.closeOnce.Do(func() {
+        first = true
+        close(m.closeChan)
+    })
+
+    conn := m.conn()
+    if conn == nil {
         return nil
     }
-    return m.opts.Conn.Close()
+    // 服务端主动关闭时通知对端, 让对端可以区分被踢出和连接断开, 优雅关闭时对端已经收到了告别帧
+    if first && m.opts.IsServerClient && !m.isDraining() && m.Status() == config.ClientConnected {
+        m.sendKick(conn)
+    }
+    return conn.Close()
 }
 
-func (m *Client) Send(data []byte) (err error) {
-    if m.Status() != config.ClientConnected {
-        return zassert.AssertError{"Client 非 ClientConnected 状态时不能使用 Send"}
-    }
-    if len(data) == 0 {
-        return
+// 是否调用过 Close
+func (m *Client) isClosing() bool {
+    select {
+    case <-m.closeChan:
+        return true
+    default:
+        return false
     }
+}
 
-    m.mx.Lock()
-    defer m.mx.Unlock()
+func (m *Client) conn() net.Conn {
+    m.connMx.RLock()
+    defer m.connMx.RUnlock()
+    return m.opts.Conn
+}
 
-    m.notifyClientSendData(m, data)
+func (m *Client) setConn(conn net.Conn) {
+    m.connMx.Lock()
+    defer m.connMx.Unlock()
+    m.opts.Conn = conn
+}
 
-    dataHeader := utils.Uint32ToBytes(uint32(len(data)))
-    if _, err = m.opts.Conn.Write(dataHeader); err != nil {
-        return nil
+// 发送数据, 开启发送队列时放入队列后立即返回
+func (m *Client) Send(data []byte) (err error) {
+    if err = m.checkSend(data); err != nil || len(data) == 0 {
+        return
     }
-    m.heartbeatTime.RefHeartbeat()
 
-    _, err = m.opts.Conn.Write(data)
-    return err
+    m.notifyClientSendData(m, data)
+    return m.interceptSend(config.FrameData, data, func(data []byte) error {
+        if len(data) == 0 {
+            return nil
+        }
+        if q := m.getSendQueue(); q != nil {
+            return m.enqueueCopy(q, &sendItem{frameType: config.FrameData, data: data})
+        }
+        return m.writeFrame(config.FrameData, nil, data)
+    })
 }
 
-func (m *Client) waitTrust(trust chan struct{}, distrust chan error) {
-    sendTrustMsg := func() error {
-        _, err := m.opts.Conn.Write(config.DefaultTrustMsg)
+func (m *Client) checkSend(data []byte) error {
+    if err := m.checkConnected(); err != nil {
         return err
     }
-    waitTrustMsg := func() error {
-        buff, err := utils.WaitConnData(m.opts.Conn, len(config.DefaultTrustMsg))
-        if err != nil {
+    if len(data) > 0 && m.isPeerGoodbye() {
+        return ErrPeerClosing
+    }
+    return nil
+}
+
+// 已经关闭时返回 ErrClosed, 未连接(例如等待重连)时返回 ErrNotConnected
+func (m *Client) checkConnected() error {
+    if m.isClosing() || m.Status() == config.ClientClosed {
+        return ErrClosed
+    }
+    if m.Status() != config.ClientConnected {
+        return ErrNotConnected
+    }
+    return nil
+}
+
+// 写入一个帧, 开启发送队列时放入队列并等待写入完成
+func (m *Client) writeFrame(frameType config.FrameType, ext []byte, data []byte) error {
+    if q := m.getSendQueue(); q != nil {
+        done := make(chan error, 1)
+        if err := m.enqueue(q, &sendItem{frameType: frameType, ext: ext, data: data, done: done}); err != nil {
             return err
         }
-        if !bytes.Equal(buff, config.DefaultTrustMsg) {
-            return errors.New("信任消息错误")
-        }
-        return nil
+        return <-done
     }
 
+    m.beginWork()
+    defer m.endWork()
+    return m.writeFrameSync(frameType, ext, data)
+}
+
+// 直接写入一个帧
+func (m *Client) writeFrameSync(frameType config.FrameType, ext []byte, data []byte) error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    m.heartbeatTime.RefHeartbeat()
+    return utils.NewProtocolError("write", m.writer.WriteFrame(m.packFrame(frameType, ext, data)))
+}
+
+// 信任结果
+const (
+    trustAccepted byte = iota
+    trustRejected
+)
+
+// 信任阶段得到的连接信息, 由 connectedHandler 加锁后发布, 避免和上一个连接的读写协程竞争
+type trustResult struct {
+    clientId   uint64
+    principal  string
+    compressor utils.Compressor
+}
+
+func (m *Client) waitTrust(conn net.Conn, trust chan *trustResult, distrust chan error) {
     if m.opts.IsServerClient {
-        if err := waitTrustMsg(); err != nil {
+        principal, err := m.opts.Authenticator.Verify(conn)
+        if err != nil {
+            _ = utils.WriteHandshakeMsg(conn, append([]byte{trustRejected}, err.Error()...))
             distrust <- err
             return
         }
 
-        if err := sendTrustMsg(); err != nil {
+        // 协商压缩器
+        names, err := utils.ReadHandshakeMsg(conn)
+        if err != nil {
             distrust <- err
             return
         }
+        compressor := m.chooseCompressor(string(names))
 
-        m.clientId = utils.AutoClientID.Next()
-        _, err := m.opts.Conn.Write(utils.Uint64ToBytes(m.clientId))
-        if err != nil {
+        clientId := utils.AutoClientID.Next()
+        result := append([]byte{trustAccepted}, utils.Uint64ToBytes(clientId)...)
+        if compressor != nil {
+            result = append(result, compressor.Name()...)
+        }
+        if err = utils.WriteHandshakeMsg(conn, result); err != nil {
             distrust <- err
             return
         }
+        trust <- &trustResult{clientId: clientId, principal: principal, compressor: compressor}
 
     } else {
-        if err := sendTrustMsg(); err != nil {
+        principal, err := m.opts.Authenticator.Authenticate(conn)
+        if err != nil {
             distrust <- err
             return
         }
-        if err := waitTrustMsg(); err != nil {
+        if err = utils.WriteHandshakeMsg(conn, m.compressorNames()); err != nil {
             distrust <- err
             return
         }
 
-        buff, err := utils.WaitConnData(m.opts.Conn, config.DataClientIdLength)
+        msg, err := utils.ReadHandshakeMsg(conn)
         if err != nil {
             distrust <- err
             return
         }
-        m.clientId = utils.BytesToUint64(buff)
+        if len(msg) > 0 && msg[0] == trustRejected {
+            distrust <- &TrustRejectedError{Reason: string(msg[1:])}
+            return
+        }
+        if len(msg) < 1+config.DataClientIdLength || msg[0] != trustAccepted {
+            distrust <- fmt.Errorf("%w: 信任结果消息错误", utils.ErrInvalidHandshake)
+            return
+        }
+
+        var compressor utils.Compressor
+        if name := string(msg[1+config.DataClientIdLength:]); name != "" {
+            if compressor = m.findCompressor(name); compressor == nil {
+                distrust <- fmt.Errorf("%w: 服务端选择了不支持的压缩器 %s", utils.ErrInvalidHandshake, name)
+                return
+            }
+        }
+        clientId := utils.BytesToUint64(msg[1 : 1+config.DataClientIdLength])
+        trust <- &trustResult{clientId: clientId, principal: principal, compressor: compressor}
     }
+}
+
+// 主动连接的客户端在这里拨号, 开启重连时断线后会按退避策略重新拨号
+func (m *Client) dialLoop() {
+    var connected bool
+    var attempt int
+    var begin time.Time
+
+    // ConnectContext 只用于首次连接, 重连时的拨号由 Close 中断
+    reconnectCtx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go func() {
+        select {
+        case <-m.closeChan:
+            cancel()
+        case <-reconnectCtx.Done():
+        }
+    }()
+
+    ctx := m.opts.ConnectContext
+    for {
+        established, err := m.dial(ctx, connected)
+        if established {
+            connected = true
+            attempt = 0
+        }
+
+        if !m.opts.Reconnect || m.isClosing() {
+            m.closedHandler(err)
+            return
+        }
+
+        if attempt == 0 {
+            begin = time.Now()
+        }
+        attempt++
+        delay := m.opts.ReconnectBackoff.Delay(attempt)
+        if m.opts.ReconnectMaxAttempts > 0 && attempt > m.opts.ReconnectMaxAttempts {
+            m.closedHandler(err)
+            return
+        }
+        if m.opts.ReconnectMaxElapsedTime > 0 && time.Since(begin)+delay > m.opts.ReconnectMaxElapsedTime {
+            m.closedHandler(err)
+            return
+        }
+
+        m.changeStatus(config.ClientReconnecting)
+        m.notifyClientReconnecting(m, attempt, delay, err)
 
-    trust <- struct{}{}
+        timer := time.NewTimer(delay)
+        select {
+        case <-timer.C:
+        case <-m.closeChan:
+            timer.Stop()
+            m.closedHandler(ErrClosed)
+            return
+        }
+        ctx = reconnectCtx
+    }
 }
 
-func (m *Client) connectedHandler() {
+// 拨号并处理连接直到断开, established 表示是否曾经连接成功
+func (m *Client) dial(ctx context.Context, reconnect bool) (established bool, err error) {
+    m.changeStatus(config.ClientConnecting)
+
+    var d net.Dialer
+    if m.opts.BindPort > 0 {
+        d.LocalAddr = &net.TCPAddr{Port: m.opts.BindPort}
+    }
+
+    conn, err := d.DialContext(ctx, m.opts.Network, m.opts.ConnectAddr)
+    if err != nil {
+        return false, utils.NewProtocolError("dial", err)
+    }
+    // tls 握手会在信任阶段第一次读写时进行
+    if m.opts.TLSConfig != nil {
+        conn = m.wrapTLS(conn)
+    }
+    m.setConn(conn)
+
+    // 拨号期间调用了 Close
+    if m.isClosing() {
+        _ = conn.Close()
+        return false, ErrClosed
+    }
+    return m.connectedHandler(reconnect)
+}
+
+func (m *Client) connectedHandler(reconnect bool) (established bool, err error) {
     m.changeStatus(config.ClientWaitTrust)
+    atomic.StoreInt32(&m.peerGoodbye, 0)
+    atomic.StoreInt32(&m.peerKicked, 0)
+    m.resetRTT()
+    m.setCause(nil)
 
-    var trust = make(chan struct{}, 1)
+    conn := m.conn()
+    var trust = make(chan *trustResult, 1)
     var distrust = make(chan error, 1)
-    var trust_time = time.NewTicker(config.DefaultWaitTrustTime)
+    var trust_timeout = make(chan struct{})
+    var trust_time = utils.AfterFunc(config.DefaultWaitTrustTime, func() { close(trust_timeout) })
 
-    go m.waitTrust(trust, distrust)
+    go m.waitTrust(conn, trust, distrust)
 
+    var result *trustResult
     select {
-    case <-trust:
+    case result = <-trust:
         trust_time.Stop()
     case err := <-distrust:
-        m.closedHandler(err)
-        return
-    case <-trust_time.C:
-        m.closedHandler(errors.New("超过最大信任等待时间"))
-        return
+        trust_time.Stop()
+        _ = conn.Close()
+        // 信任阶段调用了 Close
+        if m.isClosing() {
+            return false, ErrClosed
+        }
+        return false, utils.NewProtocolError("handshake", err)
+    case <-trust_timeout:
+        _ = conn.Close()
+        return false, ErrHandshakeTimeout
+    }
+
+    reader := m.opts.Codec.NewFrameReader(bufio.NewReaderSize(conn, m.opts.ReadBuffSize))
+    heartbeatTime := utils.NewHeartbeatTime(m.opts.HeartbeatInterval, config.DefaultHeartbeatPrecision, m.heartbeatFunc)
+    var queue *sendQueue
+    if m.opts.SendQueueSize > 0 {
+        queue = newSendQueue(m.opts.SendQueueSize, m.opts.SendQueuePolicy, m.endWork)
+        go m.sendLoop(queue)
     }
 
-    m.heartbeatTime = utils.NewHeartbeatTime(m.opts.HeartbeatInterval, config.DefaultHeartbeatPrecision, m.heartbeatFunc)
+    // compressor 只在 mx 下由 packFrame 读取, 或者由本连接的读取协程读取
+    m.mx.Lock()
+    m.heartbeatTime = heartbeatTime
+    m.writer = m.opts.Codec.NewFrameWriter(conn)
+    m.compressor = result.compressor
+    m.mx.Unlock()
+    m.connMx.Lock()
+    m.sendQueue = queue
+    m.clientId = result.clientId
+    m.principal = result.principal
+    m.connMx.Unlock()
+
     m.changeStatus(config.ClientConnected)
-    m.notifyClientConnect(m)
-    m.received()
+    if reconnect {
+        m.notifyClientReconnected(m)
+    } else {
+        m.notifyClientConnect(m)
+    }
+    m.signalConnected()
+    pingTimer := m.startPing()
+
+    err = m.disconnectCause(m.received(reader))
+    m.resetConnected()
+    heartbeatTime.Stop()
+    if pingTimer != nil {
+        pingTimer.Stop()
+    }
+    _ = conn.Close()
+    lost := &connectionLostError{cause: err}
+    if queue != nil {
+        queue.close(lost)
+    }
+    m.failCalls(lost)
+    return true, err
+}
+
+// 获取连接断开的原因
+func (m *Client) disconnectCause(err error) error {
+    if cause := m.getCause(); cause != nil {
+        return cause
+    }
+    switch {
+    case m.isClosing():
+        return ErrClosed
+    case err == nil:
+        return ErrConnectionLost
+    }
+    return err
 }
 
-func (m *Client) received() {
+func (m *Client) received(reader utils.FrameReader) error {
+    readFrame := reader.ReadFrame
+    if m.opts.PooledReceive {
+        readFrame = reader.ReadPooledFrame
+    }
+
     for m.Status() == config.ClientConnected {
-        data, err := utils.WaitConnFullData(m.opts.Conn)
+        data, err := readFrame()
         if err != nil {
-            m.closedHandler(nil)
-            return
+            return utils.NewProtocolError("read", err)
         }
 
         m.heartbeatTime.RefHeartbeat()
-        if data != nil && len(data) > 0 {
-            m.notifyClientGetData(m, data)
+        if len(data) == 0 {
+            continue
         }
+
+        err = m.handleFrame(data)
+        if m.opts.PooledReceive {
+            utils.PutBuffer(data)
+        }
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 开启 PooledReceive 时, 需要在 handleFrame 返回后继续使用的数据必须拷贝
+func (m *Client) detach(data []byte) []byte {
+    if !m.opts.PooledReceive {
+        return data
     }
+    return append([]byte(nil), data...)
+}
+
+func (m *Client) handleFrame(body []byte) error {
+    frameType, data, err := utils.ParseFrame(body)
+    if err != nil {
+        return err
+    }
+    compressed := frameType&config.FrameFlagCompressed != 0
+    frameType &= config.FrameTypeMask
+
+    switch frameType {
+    case config.FrameData:
+        if data, err = m.uncompress(data, compressed); err != nil {
+            return err
+        }
+        // 优雅关闭时丢弃新数据
+        if len(data) > 0 && m.tryBeginWork() {
+            err = m.interceptReceive(frameType, data, func(data []byte) error {
+                if len(data) > 0 {
+                    m.notifyClientGetData(m, data)
+                }
+                return nil
+            })
+            m.endWork()
+            if err != nil {
+                return err
+            }
+        }
+    case config.FrameRequest:
+        requestId, data, err := utils.ParseCallFrame(data)
+        if err != nil {
+            return err
+        }
+        if data, err = m.uncompress(data, compressed); err != nil {
+            return err
+        }
+        if !m.tryBeginWork() {
+            go func() {
+                _ = m.writeErrorResponse(requestId, ErrShuttingDown)
+            }()
+            break
+        }
+        // 接收拦截器和 CallHandler 一起在新的协程中执行, 拦截器可以在 next 之后看到处理结果
+        data = m.detach(data)
+        go func() {
+            defer m.endWork()
+            m.handleRequest(requestId, data)
+        }()
+    case config.FrameResponse:
+        requestId, data, err := utils.ParseCallFrame(data)
+        if err != nil {
+            return err
+        }
+        if data, err = m.uncompress(data, compressed); err != nil {
+            return err
+        }
+        err = m.interceptReceive(frameType, data, func(data []byte) error {
+            m.handleResponse(requestId, &callResult{data: m.detach(data)})
+            return nil
+        })
+        if err != nil {
+            return err
+        }
+    case config.FrameErrorResponse:
+        requestId, data, err := utils.ParseCallFrame(data)
+        if err != nil {
+            return err
+        }
+        code, data, err := utils.ParseErrorResponse(data)
+        if err != nil {
+            return err
+        }
+        if data, err = m.uncompress(data, compressed); err != nil {
+            return err
+        }
+        err = m.interceptReceive(frameType, data, func(data []byte) error {
+            m.handleResponse(requestId, &callResult{err: CallError{Code: code, Msg: string(data)}})
+            return nil
+        })
+        if err != nil {
+            return err
+        }
+    case config.FrameRoute:
+        route, data, err := utils.ParseRouteFrame(data)
+        if err != nil {
+            return err
+        }
+        if data, err = m.uncompress(data, compressed); err != nil {
+            return err
+        }
+        if m.tryBeginWork() {
+            err = m.interceptReceive(frameType, data, func(data []byte) error {
+                m.opts.Router.Dispatch(m, route, data)
+                return nil
+            })
+            m.endWork()
+            if err != nil {
+                return err
+            }
+        }
+    case config.FrameGoodbye:
+        atomic.StoreInt32(&m.peerGoodbye, 1)
+    case config.FramePing:
+        timestamp, err := utils.ParsePingFrame(data)
+        if err != nil {
+            return err
+        }
+        m.replyPong(timestamp)
+    case config.FramePong:
+        timestamp, err := utils.ParsePingFrame(data)
+        if err != nil {
+            return err
+        }
+        m.handlePong(timestamp)
+    case config.FrameKick:
+        atomic.StoreInt32(&m.peerKicked, 1)
+    default:
+        return fmt.Errorf("%w: 未知的帧类型 %d", utils.ErrInvalidFrame, frameType)
+    }
+    return nil
 }
 
 func (m *Client) heartbeatFunc(timer *utils.HeartbeatTime) {
     if m.opts.IsServerClient {
-        _ = m.Close()
+        m.closeConn(ErrHeartbeatTimeout)
     } else {
-        m.mx.Lock()
-        defer m.mx.Unlock()
-
-        // 发送一个空数据来表示心跳
-        _, _ = m.opts.Conn.Write(make([]byte, config.DataHeaderLength))
+        // 发送 ping 作为心跳, 对端回复 pong 时可以得到往返时间
+        _ = m.writeFrameSync(config.FramePing, pingTimestamp(), nil)
     }
 }
 
+// err 为导致关闭的错误, 会转为 CloseReason 传给关闭观察者
 func (m *Client) closedHandler(err error) {
     if m.Status() != config.ClientClosed {
         m.changeStatus(config.ClientClosed)
-        if m.heartbeatTime != nil {
-            m.heartbeatTime.Stop()
-        }
-
-        m.notifyClientClose(m, err)
+        m.closeReason = m.newCloseReason(err)
+        m.cancel()
+        m.notifyClientClose(m, m.closeReason)
+        // 关闭观察者中仍然可以读取会话数据
+        m.session.Clear()
+        close(m.closedChan)
     }
 }
 
@@ -262,3 +700,13 @@ func (m *Client) notifyClientGetData(c *Client, data []byte) {
         fn(c, data)
     }
 }
+func (m *Client) notifyClientReconnecting(c *Client, attempt int, delay time.Duration, err error) {
+    for _, fn := range c.opts.ClientReconnectingObserves {
+        fn(c, attempt, delay, err)
+    }
+}
+func (m *Client) notifyClientReconnected(c *Client) {
+    for _, fn := range c.opts.ClientReconnectedObserves {
+        fn(c)
+    }
+}
@@ -0,0 +1,129 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "errors"
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
+    "io"
+    "net"
+    "sync/atomic"
+    "time"
+)
+
+// 发送踢出帧的最长时间, 对端不读取数据时不能让 Close 一直阻塞
+var kickWriteTimeout time.Duration = 1e9
+
+// 客户端关闭的原因, 会作为 err 传给 ClientCloseObserve
+type CloseReason struct {
+    // 关闭原因
+    Code config.CloseCode
+    // 发起关闭的一方
+    Source config.CloseSource
+    // 底层错误, 可以用 errors.Is 和 errors.As 判断
+    Err error
+}
+
+func (m *CloseReason) Error() string {
+    if m.Err == nil {
+        return m.Code.String()
+    }
+    return m.Code.String() + ": " + m.Err.Error()
+}
+
+func (m *CloseReason) Unwrap() error {
+    return m.Err
+}
+
+// 获取客户端关闭的原因, 未关闭时返回 nil
+func (m *Client) CloseReason() *CloseReason {
+    select {
+    case <-m.closedChan:
+        return m.closeReason
+    default:
+        return nil
+    }
+}
+
+// 根据导致关闭的错误生成关闭原因
+func (m *Client) newCloseReason(err error) *CloseReason {
+    reason := &CloseReason{Code: config.CloseError, Source: config.CloseSourceLocal, Err: err}
+
+    var pe *utils.ProtocolError
+    errors.As(err, &pe)
+
+    switch {
+    case errors.Is(err, ErrClosed):
+        reason.Code = config.CloseLocal
+        if m.isDraining() {
+            reason.Code = config.CloseShutdown
+        }
+    case errors.Is(err, ErrHeartbeatTimeout):
+        reason.Code = config.CloseHeartbeatTimeout
+    case errors.Is(err, ErrSendQueueFull):
+        reason.Code = config.CloseSendQueueFull
+    case errors.Is(err, ErrTrustRejected):
+        reason.Code, reason.Source = config.CloseHandshakeFailed, config.CloseSourceRemote
+    case errors.Is(err, ErrHandshakeTimeout), pe != nil && pe.Op == "handshake":
+        reason.Code = config.CloseHandshakeFailed
+    case pe != nil && pe.Op == "dial":
+        reason.Code = config.CloseDialFailed
+    case errors.Is(err, utils.ErrFrameTooLarge):
+        reason.Code = config.CloseFrameTooLarge
+    case errors.Is(err, utils.ErrInvalidFrame):
+        reason.Code = config.CloseProtocolError
+    case m.isPeerKicked() && (errors.Is(err, io.EOF) || errors.Is(err, ErrConnectionLost) || pe != nil && pe.Op == "read"):
+        reason.Code, reason.Source = config.ClosePeerKicked, config.CloseSourceRemote
+    case errors.Is(err, io.EOF), errors.Is(err, ErrConnectionLost):
+        reason.Code, reason.Source = config.ClosePeerClosed, config.CloseSourceRemote
+        if m.isPeerGoodbye() {
+            reason.Code = config.ClosePeerGoodbye
+        }
+    case pe != nil && pe.Op == "read":
+        reason.Code, reason.Source = config.CloseNetworkError, config.CloseSourceRemote
+    }
+    return reason
+}
+
+// 对端是否发来了踢出帧
+func (m *Client) isPeerKicked() bool {
+    return atomic.LoadInt32(&m.peerKicked) == 1
+}
+
+// 发送踢出帧, 之后连接会被关闭, 所以可以直接设置写入超时
+func (m *Client) sendKick(conn net.Conn) {
+    _ = conn.SetWriteDeadline(time.Now().Add(kickWriteTimeout))
+    _ = m.writeFrameSync(config.FrameKick, nil, nil)
+}
+
+// 记录原因后关闭连接, 用于和主动调用 Close 区分
+func (m *Client) closeConn(cause error) {
+    m.causeMx.Lock()
+    if m.cause == nil {
+        m.cause = cause
+    }
+    m.causeMx.Unlock()
+
+    if conn := m.conn(); conn != nil {
+        _ = conn.Close()
+    }
+}
+
+func (m *Client) getCause() error {
+    m.causeMx.Lock()
+    defer m.causeMx.Unlock()
+    return m.cause
+}
+
+func (m *Client) setCause(cause error) {
+    m.causeMx.Lock()
+    m.cause = cause
+    m.causeMx.Unlock()
+}
@@ -0,0 +1,69 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "fmt"
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
+    "strings"
+)
+
+// 本地支持的压缩器名称, 按优先级使用逗号连接
+func (m *Client) compressorNames() []byte {
+    names := make([]string, len(m.opts.Compressors))
+    for i, c := range m.opts.Compressors {
+        names[i] = c.Name()
+    }
+    return []byte(strings.Join(names, ","))
+}
+
+func (m *Client) findCompressor(name string) utils.Compressor {
+    for _, c := range m.opts.Compressors {
+        if c.Name() == name {
+            return c
+        }
+    }
+    return nil
+}
+
+// 按对端的优先级选择第一个本地也支持的压缩器, 都不支持时返回 nil
+func (m *Client) chooseCompressor(names string) utils.Compressor {
+    if names == "" {
+        return nil
+    }
+    for _, name := range strings.Split(names, ",") {
+        if c := m.findCompressor(name); c != nil {
+            return c
+        }
+    }
+    return nil
+}
+
+// 生成帧头并在需要时压缩数据, 调用者需要持有 mx
+func (m *Client) packFrame(frameType config.FrameType, ext []byte, data []byte) ([]byte, []byte) {
+    if m.compressor != nil && len(data) > 0 && len(data) >= m.opts.CompressThreshold {
+        // 压缩后没有变小时发送原始数据
+        if compressed, err := m.compressor.Compress(data); err == nil && len(compressed) < len(data) {
+            return utils.FrameHead(frameType|config.FrameFlagCompressed, ext), compressed
+        }
+    }
+    return utils.FrameHead(frameType, ext), data
+}
+
+// 解压数据
+func (m *Client) uncompress(data []byte, compressed bool) ([]byte, error) {
+    if !compressed {
+        return data, nil
+    }
+    if m.compressor == nil {
+        return nil, fmt.Errorf("%w: 收到了压缩的数据, 但是没有协商压缩器", utils.ErrInvalidFrame)
+    }
+    return m.compressor.Decompress(data)
+}
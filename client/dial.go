@@ -0,0 +1,69 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "context"
+)
+
+// 创建客户端并阻塞到信任阶段完成, 失败时返回错误
+//
+// ctx 同时作为首次拨号的 ConnectContext(可以用 WithConnectContext 覆盖), ctx 结束时关闭客户端并返回 ctx.Err().
+// 开启重连时会一直等待到连接成功, 或者达到重连限制后客户端关闭
+func Dial(ctx context.Context, opts ...Option) (*Client, error) {
+    opts = append([]Option{WithConnectContext(ctx)}, opts...)
+    c, err := NewClient(opts...)
+    if err != nil {
+        return nil, err
+    }
+
+    if err = c.WaitConnected(ctx); err != nil {
+        _ = c.Close()
+        return nil, err
+    }
+    return c, nil
+}
+
+// 等待客户端连接成功并且连接观察者执行完毕, 已连接时立即返回
+//
+// 客户端关闭时返回 *CloseReason, ctx 结束时返回 ctx.Err()
+func (m *Client) WaitConnected(ctx context.Context) error {
+    select {
+    case <-m.connectedSignal():
+        return nil
+    case <-m.closedChan:
+        return m.closeReason
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+func (m *Client) connectedSignal() chan struct{} {
+    m.connectedMx.Lock()
+    defer m.connectedMx.Unlock()
+    return m.connectedChan
+}
+
+// 通知等待者已经连接成功
+func (m *Client) signalConnected() {
+    m.connectedMx.Lock()
+    defer m.connectedMx.Unlock()
+    close(m.connectedChan)
+}
+
+// 连接断开后重置, 之后的等待者需要等待下一次连接成功
+func (m *Client) resetConnected() {
+    m.connectedMx.Lock()
+    defer m.connectedMx.Unlock()
+    select {
+    case <-m.connectedChan:
+        m.connectedChan = make(chan struct{})
+    default:
+    }
+}
@@ -0,0 +1,90 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "errors"
+    "github.com/zlyuancn/ztcp/utils"
+)
+
+var (
+    // 客户端不是 ClientConnected 状态, 例如正在连接或等待重连, 客户端已关闭时使用 ErrClosed
+    ErrNotConnected = errors.New("客户端未连接")
+    // 客户端已关闭
+    ErrClosed = errors.New("客户端已关闭")
+    // 连接已断开
+    ErrConnectionLost = errors.New("连接已断开")
+    // 对端发来了告别帧, 正在关闭
+    ErrPeerClosing = errors.New("对端正在关闭")
+    // 未设置 ConnectAddr
+    ErrNoConnectAddr = errors.New("未设置 ConnectAddr, 请使用 WithConnectAddr(addr)")
+    // 信任阶段超时
+    ErrHandshakeTimeout = errors.New("超过最大信任等待时间")
+    // 对端拒绝信任, 具体信息见 TrustRejectedError
+    ErrTrustRejected = errors.New("对端拒绝信任")
+    // 认证失败
+    ErrAuthFailed = errors.New("认证失败")
+    // 在心跳检测时间内没有收到对端的数据
+    ErrHeartbeatTimeout = errors.New("心跳超时")
+    // 发送队列已满
+    ErrSendQueueFull = errors.New("发送队列已满")
+    // 正在优雅关闭, 不再处理对端的请求
+    ErrShuttingDown = errors.New("正在关闭, 不再处理请求")
+    // 未设置 CallHandler
+    ErrNoCallHandler = errors.New("未设置 CallHandler")
+
+    // 以下错误定义在 utils 中, 这里导出方便使用
+
+    // 数据长度超过限制
+    ErrFrameTooLarge = utils.ErrFrameTooLarge
+    // 帧数据格式错误
+    ErrInvalidFrame = utils.ErrInvalidFrame
+    // 握手消息格式错误
+    ErrInvalidHandshake = utils.ErrInvalidHandshake
+)
+
+// 协议错误, 包装了读写连接时的底层错误
+type ProtocolError = utils.ProtocolError
+
+// 数据长度超过限制
+type FrameTooLargeError = utils.FrameTooLargeError
+
+// 对端拒绝信任, 可以用 errors.Is(err, ErrTrustRejected) 判断
+type TrustRejectedError struct {
+    // 对端给出的原因
+    Reason string
+}
+
+func (m *TrustRejectedError) Error() string {
+    return "对端拒绝信任: " + m.Reason
+}
+
+func (m *TrustRejectedError) Is(target error) bool {
+    return target == ErrTrustRejected
+}
+
+// 连接断开导致发送或者请求失败, 可以用 errors.Is(err, ErrConnectionLost) 判断, 也可以判断断开的原因
+type connectionLostError struct {
+    cause error
+}
+
+func (m *connectionLostError) Error() string {
+    if m.cause == ErrConnectionLost {
+        return m.cause.Error()
+    }
+    return ErrConnectionLost.Error() + ": " + m.cause.Error()
+}
+
+func (m *connectionLostError) Is(target error) bool {
+    return target == ErrConnectionLost
+}
+
+func (m *connectionLostError) Unwrap() error {
+    return m.cause
+}
@@ -0,0 +1,51 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "github.com/zlyuancn/ztcp/config"
+)
+
+// 调用下一个拦截器, 最后一个拦截器的 next 会真正的发送或处理数据
+type InterceptorNext func(data []byte) error
+
+// 拦截器, 作用于 FrameData, FrameRoute, FrameRequest, FrameResponse 和 FrameErrorResponse 的数据,
+// FrameErrorResponse 的数据是错误信息
+//
+// 拦截器可以修改数据后调用 next, 不调用 next 表示丢弃该帧, 也可以在 next 前后做其它事情.
+// 发送拦截器返回的错误会返回给 Send 等发送方法, 接收拦截器返回错误时会断开连接.
+// 接收 FrameRequest 时 next 会执行 CallHandler 并返回它的错误, 这时拦截器返回的错误会作为错误响应发给对端, 不会断开连接
+type Interceptor func(c *Client, frameType config.FrameType, data []byte, next InterceptorNext) error
+
+func (m *Client) intercept(interceptors []Interceptor, frameType config.FrameType, data []byte, final InterceptorNext) error {
+    if len(interceptors) == 0 {
+        return final(data)
+    }
+
+    var next func(i int, data []byte) error
+    next = func(i int, data []byte) error {
+        if i == len(interceptors) {
+            return final(data)
+        }
+        return interceptors[i](m, frameType, data, func(data []byte) error {
+            return next(i+1, data)
+        })
+    }
+    return next(0, data)
+}
+
+// 经过发送拦截器后调用 send
+func (m *Client) interceptSend(frameType config.FrameType, data []byte, send InterceptorNext) error {
+    return m.intercept(m.opts.SendInterceptors, frameType, data, send)
+}
+
+// 经过接收拦截器后调用 handle
+func (m *Client) interceptReceive(frameType config.FrameType, data []byte, handle InterceptorNext) error {
+    return m.intercept(m.opts.ReceiveInterceptors, frameType, data, handle)
+}
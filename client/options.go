@@ -10,26 +10,57 @@ package client
 
 import (
     "context"
+    "crypto/tls"
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
     "net"
     "time"
 )
 
+//默认网络类型
+var DefaultNetwork = "tcp"
+
 //心跳发送时间(推荐为心跳检测时间的 2/5
 var DefaultHeartbeatInterval time.Duration = 16e9
 
+//请求默认超时时间
+var DefaultCallTimeout time.Duration = 30e9
+
+//压缩阈值, 数据长度小于该值时不压缩
+var DefaultCompressThreshold = 1024
+
+//重连初始间隔
+var DefaultReconnectInitialInterval time.Duration = 5e8
+//重连最大间隔
+var DefaultReconnectMaxInterval time.Duration = 30e9
+//重连间隔倍数
+var DefaultReconnectMultiplier = 2.0
+//重连间隔随机抖动系数
+var DefaultReconnectJitter = 0.2
+
 type Option func(opts *Options)
 
 type ClientConnectObserve func(c *Client)
+// 客户端关闭观察者, err 一定是 *CloseReason, 包含关闭原因和导致关闭的错误
 type ClientCloseObserve func(c *Client, err error)
 type ClientSendDataObserve func(c *Client, data []byte)
 type ClientGetDataObserve func(c *Client, data []byte)
+type ClientReconnectingObserve func(c *Client, attempt int, delay time.Duration, err error)
+type ClientReconnectedObserve func(c *Client)
+// 收到 pong 观察者, rtt 为这次的往返时间
+type ClientPongObserve func(c *Client, rtt time.Duration)
+
+// 请求处理函数, 返回的数据或错误会作为响应发送给对端
+type CallHandler func(c *Client, data []byte) ([]byte, error)
 
 type Options struct {
     IsServerClient bool
     Conn           net.Conn
-    // bind本地端口
+    // 网络类型, 可以是 tcp, tcp4, tcp6, unix
+    Network string
+    // bind本地端口, 0表示不绑定, 只对 tcp 有效
     BindPort int
-    // 要连接的地址
+    // 要连接的地址, 网络类型为 unix 时是 socket 文件路径
     ConnectAddr string
     // 可以用于连接超时
     ConnectContext context.Context
@@ -43,12 +74,72 @@ type Options struct {
     ClientGetDataObserves []ClientGetDataObserve
     // 心跳间隔时间
     HeartbeatInterval time.Duration
+    // 断线后是否自动重连(仅对主动连接的客户端有效)
+    Reconnect bool
+    // 重连退避策略
+    ReconnectBackoff utils.Backoff
+    // 最大连续重连次数, 0表示不限制
+    ReconnectMaxAttempts int
+    // 从断线开始允许重连的最长时间, 0表示不限制
+    ReconnectMaxElapsedTime time.Duration
+    // 重连中观察者, 每次等待重连前触发
+    ClientReconnectingObserves []ClientReconnectingObserve
+    // 重连成功观察者
+    ClientReconnectedObserves []ClientReconnectedObserve
+    // 收到 pong 观察者
+    ClientPongObserves []ClientPongObserve
+    // 定时发送 ping 的间隔, 和心跳无关, 0表示不定时发送
+    PingInterval time.Duration
+    // 请求处理函数
+    CallHandler CallHandler
+    // 请求超时时间, ctx 没有截止时间时使用
+    CallTimeout time.Duration
+    // 分帧编解码器
+    Codec utils.Codec
+    // tls 配置, 为 nil 时不使用 tls
+    TLSConfig *tls.Config
+    // 信任阶段的认证器
+    Authenticator Authenticator
+    // 发送队列容量, 0表示不使用发送队列, 直接在调用者的协程中写入
+    SendQueueSize int
+    // 发送队列满时的策略
+    SendQueuePolicy config.SendQueuePolicy
+    // 接收数据时使用缓冲池, 开启后 ClientGetDataObserve, 路由处理函数和接收拦截器收到的数据在返回后会被回收
+    PooledReceive bool
+    // 连接读取缓冲大小
+    ReadBuffSize int
+    // 支持的压缩器, 按优先级排列, 在信任阶段和对端协商
+    Compressors []utils.Compressor
+    // 数据长度小于该值时不压缩
+    CompressThreshold int
+    // 路由器
+    Router *Router
+    // 发送拦截器, 第一个在最外层
+    SendInterceptors []Interceptor
+    // 接收拦截器, 第一个在最外层
+    ReceiveInterceptors []Interceptor
+    // SendValue 和 HandleValue 使用的序列化器
+    Serializer utils.Serializer
 }
 
 func newOptions(opts ...Option) *Options {
     opt := &Options{
+        Network:           DefaultNetwork,
         HeartbeatInterval: DefaultHeartbeatInterval,
         ConnectContext:    context.Background(),
+        CallTimeout:       DefaultCallTimeout,
+        Codec:             utils.DefaultCodec,
+        Authenticator:     NewTrustMsgAuthenticator(nil),
+        CompressThreshold: DefaultCompressThreshold,
+        ReadBuffSize:      utils.ReadBuffSize,
+        Router:            NewRouter(),
+        Serializer:        utils.DefaultSerializer,
+        ReconnectBackoff: utils.Backoff{
+            InitialInterval: DefaultReconnectInitialInterval,
+            MaxInterval:     DefaultReconnectMaxInterval,
+            Multiplier:      DefaultReconnectMultiplier,
+            Jitter:          DefaultReconnectJitter,
+        },
     }
 
     for _, o := range opts {
@@ -70,6 +161,13 @@ func WithBindPort(port int) Option {
     }
 }
 
+// 设置网络类型, 可以是 tcp, tcp4, tcp6, unix, 需要和服务端的网络类型一致
+func WithNetwork(network string) Option {
+    return func(opts *Options) {
+        opts.Network = network
+    }
+}
+
 func WithConnectAddr(addr string) Option {
     return func(opts *Options) {
         opts.ConnectAddr = addr
@@ -111,3 +209,153 @@ func WithHeartbeatInterval(interval time.Duration) Option {
         opts.HeartbeatInterval = interval
     }
 }
+
+// 开启断线自动重连
+func WithReconnect(enable bool) Option {
+    return func(opts *Options) {
+        opts.Reconnect = enable
+    }
+}
+
+// 设置重连退避策略(初始间隔, 最大间隔, 间隔倍数, 随机抖动系数)
+func WithReconnectBackoff(initial, max time.Duration, multiplier, jitter float64) Option {
+    return func(opts *Options) {
+        opts.ReconnectBackoff = utils.Backoff{
+            InitialInterval: initial,
+            MaxInterval:     max,
+            Multiplier:      multiplier,
+            Jitter:          jitter,
+        }
+    }
+}
+
+func WithReconnectMaxAttempts(attempts int) Option {
+    return func(opts *Options) {
+        opts.ReconnectMaxAttempts = attempts
+    }
+}
+
+func WithReconnectMaxElapsedTime(d time.Duration) Option {
+    return func(opts *Options) {
+        opts.ReconnectMaxElapsedTime = d
+    }
+}
+
+func WithClientReconnectingObserves(observers ...ClientReconnectingObserve) Option {
+    return func(opts *Options) {
+        opts.ClientReconnectingObserves = append(opts.ClientReconnectingObserves, observers...)
+    }
+}
+
+func WithClientReconnectedObserves(observers ...ClientReconnectedObserve) Option {
+    return func(opts *Options) {
+        opts.ClientReconnectedObserves = append(opts.ClientReconnectedObserves, observers...)
+    }
+}
+
+func WithClientPongObserves(observers ...ClientPongObserve) Option {
+    return func(opts *Options) {
+        opts.ClientPongObserves = append(opts.ClientPongObserves, observers...)
+    }
+}
+
+// 定时发送 ping 更新往返时间, 有数据往来时也会发送, 精度为心跳时间精度
+func WithPingInterval(interval time.Duration) Option {
+    return func(opts *Options) {
+        opts.PingInterval = interval
+    }
+}
+
+func WithCallHandler(handler CallHandler) Option {
+    return func(opts *Options) {
+        opts.CallHandler = handler
+    }
+}
+
+func WithCallTimeout(timeout time.Duration) Option {
+    return func(opts *Options) {
+        opts.CallTimeout = timeout
+    }
+}
+
+func WithCodec(codec utils.Codec) Option {
+    return func(opts *Options) {
+        opts.Codec = codec
+    }
+}
+
+// 设置 tls 配置, 设置 Certificates 可以向服务端提供客户端证书
+func WithTLSConfig(config *tls.Config) Option {
+    return func(opts *Options) {
+        opts.TLSConfig = config
+    }
+}
+
+func WithAuthenticator(authenticator Authenticator) Option {
+    return func(opts *Options) {
+        opts.Authenticator = authenticator
+    }
+}
+
+// 开启发送队列, 由单独的协程写入数据, 慢速的对端不会阻塞发送者
+//
+// 放入队列时会拷贝数据, Send 返回后可以复用 data
+func WithSendQueue(size int, policy config.SendQueuePolicy) Option {
+    return func(opts *Options) {
+        opts.SendQueueSize = size
+        opts.SendQueuePolicy = policy
+    }
+}
+
+// 接收数据时使用缓冲池, 开启后以下回调收到的数据只在回调期间有效, 需要在回调返回后使用时必须拷贝:
+// ClientGetDataObserve, 路由处理函数(包括中间件和 HandleValue 的处理函数收到的原始数据)和接收拦截器.
+// 请求和响应的数据会被拷贝, 不受影响. 在回调中调用 Send 等发送方法是安全的
+func WithPooledReceive(enable bool) Option {
+    return func(opts *Options) {
+        opts.PooledReceive = enable
+    }
+}
+
+// 设置连接读取缓冲大小, 每个连接占用一个, 帧比缓冲大时需要多次读取
+func WithReadBuffSize(size int) Option {
+    return func(opts *Options) {
+        opts.ReadBuffSize = size
+    }
+}
+
+// 设置支持的压缩器, 按优先级排列, 在信任阶段和对端协商使用双方都支持的压缩器
+func WithCompressors(compressors ...utils.Compressor) Option {
+    return func(opts *Options) {
+        opts.Compressors = append(opts.Compressors, compressors...)
+    }
+}
+
+func WithCompressThreshold(threshold int) Option {
+    return func(opts *Options) {
+        opts.CompressThreshold = threshold
+    }
+}
+
+func WithRouter(router *Router) Option {
+    return func(opts *Options) {
+        opts.Router = router
+    }
+}
+
+func WithSendInterceptors(interceptors ...Interceptor) Option {
+    return func(opts *Options) {
+        opts.SendInterceptors = append(opts.SendInterceptors, interceptors...)
+    }
+}
+
+func WithReceiveInterceptors(interceptors ...Interceptor) Option {
+    return func(opts *Options) {
+        opts.ReceiveInterceptors = append(opts.ReceiveInterceptors, interceptors...)
+    }
+}
+
+func WithSerializer(serializer utils.Serializer) Option {
+    return func(opts *Options) {
+        opts.Serializer = serializer
+    }
+}
@@ -0,0 +1,121 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
+    "time"
+)
+
+// ping 时间戳的起点, 使用单调时钟, 不受系统时间调整影响
+var pingEpoch = time.Now()
+
+// 往返时间统计, 每次连接成功后重置
+type RTTStats struct {
+    // 最近一次的往返时间
+    Last time.Duration
+    // 平滑往返时间, 和 tcp 的 srtt 一样使用 1/8 的权重
+    Smoothed time.Duration
+    // 最小往返时间
+    Min time.Duration
+    // 最大往返时间
+    Max time.Duration
+    // 收到的 pong 数量
+    Count int64
+}
+
+// 获取往返时间统计, 没有收到过 pong 时 Count 为 0
+func (m *Client) RTT() RTTStats {
+    m.rttMx.Lock()
+    defer m.rttMx.Unlock()
+    return m.rtt
+}
+
+// 发送一个 ping, 收到 pong 时更新往返时间并通知 pong 观察者
+//
+// 主动连接的客户端会在心跳时自动发送 ping, 设置 PingInterval 后会定时发送
+func (m *Client) Ping() error {
+    if err := m.checkSend(nil); err != nil {
+        return err
+    }
+    return m.writeFrame(config.FramePing, pingTimestamp(), nil)
+}
+
+// 按 PingInterval 定时发送 ping, 没有设置时返回 nil
+func (m *Client) startPing() *utils.WheelTimer {
+    if m.opts.PingInterval <= 0 {
+        return nil
+    }
+
+    var timer *utils.WheelTimer
+    timer = utils.SharedTimingWheel(config.DefaultHeartbeatPrecision).NewTimer(func() {
+        _ = m.Ping()
+        timer.Rearm(m.opts.PingInterval)
+    })
+    timer.Reset(m.opts.PingInterval)
+    return timer
+}
+
+// 回复 pong, 在读取协程中执行, 不能等待发送队列
+func (m *Client) replyPong(timestamp uint64) {
+    ext := utils.Uint64ToBytes(timestamp)
+    m.beginWork()
+    if q := m.getSendQueue(); q != nil {
+        // 队列满时丢弃, 对端只是少了一次往返时间
+        if q.tryPush(&sendItem{frameType: config.FramePong, ext: ext}) != nil {
+            m.endWork()
+        }
+        return
+    }
+
+    defer m.endWork()
+    _ = m.writeFrameSync(config.FramePong, ext, nil)
+}
+
+func pingTimestamp() []byte {
+    return utils.Uint64ToBytes(uint64(time.Since(pingEpoch)))
+}
+
+func (m *Client) handlePong(timestamp uint64) {
+    rtt := time.Since(pingEpoch) - time.Duration(timestamp)
+    if rtt < 0 {
+        rtt = 0
+    }
+
+    m.rttMx.Lock()
+    if m.rtt.Count == 0 {
+        m.rtt = RTTStats{Last: rtt, Smoothed: rtt, Min: rtt, Max: rtt, Count: 1}
+    } else {
+        m.rtt.Last = rtt
+        m.rtt.Smoothed += (rtt - m.rtt.Smoothed) / 8
+        if rtt < m.rtt.Min {
+            m.rtt.Min = rtt
+        }
+        if rtt > m.rtt.Max {
+            m.rtt.Max = rtt
+        }
+        m.rtt.Count++
+    }
+    m.rttMx.Unlock()
+
+    m.notifyClientPong(m, rtt)
+}
+
+func (m *Client) resetRTT() {
+    m.rttMx.Lock()
+    m.rtt = RTTStats{}
+    m.rttMx.Unlock()
+}
+
+func (m *Client) notifyClientPong(c *Client, rtt time.Duration) {
+    for _, fn := range c.opts.ClientPongObserves {
+        fn(c, rtt)
+    }
+}
@@ -0,0 +1,245 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "fmt"
+    "github.com/zlyuancn/ztcp/config"
+    "sync"
+)
+
+// 发送队列中的一个帧
+type sendItem struct {
+    frameType config.FrameType
+    ext       []byte
+    data      []byte
+    // 写入完成后接收写入结果, 可以为 nil
+    done chan error
+}
+
+// 每个连接的发送队列, 由单独的写入协程消费
+type sendQueue struct {
+    mx     sync.Mutex
+    cond   *sync.Cond
+    items  []*sendItem
+    size   int
+    policy config.SendQueuePolicy
+    closed bool
+    // 每个帧处理完毕后调用
+    finished func()
+}
+
+func newSendQueue(size int, policy config.SendQueuePolicy, finished func()) *sendQueue {
+    q := &sendQueue{
+        size:     size,
+        policy:   policy,
+        finished: finished,
+    }
+    q.cond = sync.NewCond(&q.mx)
+    return q
+}
+
+func (m *sendQueue) finish(item *sendItem, err error) {
+    if item.done != nil {
+        item.done <- err
+    }
+    m.finished()
+}
+
+// 放入一个帧, 队列满时按策略处理, 策略为 SendQueueDisconnect 时由调用者断开连接
+func (m *sendQueue) push(item *sendItem) error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if m.closed {
+        return ErrConnectionLost
+    }
+
+    if len(m.items) >= m.size {
+        switch m.policy {
+        case config.SendQueueDropNewest:
+            return ErrSendQueueFull
+        case config.SendQueueDropOldest:
+            oldest := m.items[0]
+            m.items = m.items[1:]
+            m.finish(oldest, fmt.Errorf("%w, 数据被丢弃", ErrSendQueueFull))
+        case config.SendQueueDisconnect:
+            return fmt.Errorf("%w, 断开连接", ErrSendQueueFull)
+        default:
+            for len(m.items) >= m.size && !m.closed {
+                m.cond.Wait()
+            }
+            if m.closed {
+                return ErrConnectionLost
+            }
+        }
+    }
+
+    m.items = append(m.items, item)
+    m.cond.Broadcast()
+    return nil
+}
+
+// 放入一个帧, 队列满时不按策略处理, 直接返回 ErrSendQueueFull
+func (m *sendQueue) tryPush(item *sendItem) error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if m.closed {
+        return ErrConnectionLost
+    }
+    if len(m.items) >= m.size {
+        return ErrSendQueueFull
+    }
+
+    m.items = append(m.items, item)
+    m.cond.Broadcast()
+    return nil
+}
+
+// 取出队列中所有的帧, 队列为空时阻塞, 队列关闭后返回 false
+func (m *sendQueue) popAll() ([]*sendItem, bool) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    for len(m.items) == 0 && !m.closed {
+        m.cond.Wait()
+    }
+    if len(m.items) == 0 {
+        return nil, false
+    }
+
+    items := m.items
+    m.items = nil
+    m.cond.Broadcast()
+    return items, true
+}
+
+// 关闭队列, 未写入的帧全部失败
+func (m *sendQueue) close(err error) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if m.closed {
+        return
+    }
+    m.closed = true
+    for _, item := range m.items {
+        m.finish(item, err)
+    }
+    m.items = nil
+    m.cond.Broadcast()
+}
+
+// 写入协程, 每次将队列中所有的帧合并为一次 writev 写入
+func (m *Client) sendLoop(q *sendQueue) {
+    for {
+        items, ok := q.popAll()
+        if !ok {
+            return
+        }
+
+        errs := m.writeFramesSync(items)
+        for i, item := range items {
+            q.finish(item, errs[i])
+        }
+    }
+}
+
+// 直接写入多个帧, 返回每个帧的写入结果
+func (m *Client) writeFramesSync(items []*sendItem) []error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    errs := make([]error, len(items))
+    for i, item := range items {
+        errs[i] = m.writer.BufferFrame(m.packFrame(item.frameType, item.ext, item.data))
+    }
+    m.heartbeatTime.RefHeartbeat()
+
+    if err := m.writer.Flush(); err != nil {
+        for i := range errs {
+            if errs[i] == nil {
+                errs[i] = err
+            }
+        }
+    }
+    return errs
+}
+
+// 不能使用 mx, 写入协程在慢速写入时会一直持有 mx
+func (m *Client) getSendQueue() *sendQueue {
+    m.connMx.RLock()
+    defer m.connMx.RUnlock()
+    return m.sendQueue
+}
+
+// 放入发送队列, 开始追踪写入
+func (m *Client) enqueue(q *sendQueue, item *sendItem) error {
+    m.beginWork()
+    err := q.push(item)
+    if err == nil {
+        return nil
+    }
+
+    m.endWork()
+    if m.opts.SendQueuePolicy == config.SendQueueDisconnect {
+        m.closeConn(err)
+    }
+    return err
+}
+
+// 放入队列的数据在调用返回之后才会写入, 拷贝一份让调用者可以立即复用自己的缓冲,
+// 例如开启 PooledReceive 时在 ClientGetDataObserve 中直接发送收到的数据
+func (m *Client) enqueueCopy(q *sendQueue, item *sendItem) error {
+    item.data = append([]byte(nil), item.data...)
+    return m.enqueue(q, item)
+}
+
+// 异步发送数据, 返回的 chan 会接收写入结果
+//
+// 未开启发送队列时会在新的协程中发送, data 会被拷贝, 调用返回后可以复用
+func (m *Client) SendAsync(data []byte) <-chan error {
+    done := make(chan error, 1)
+
+    q := m.getSendQueue()
+    if q == nil {
+        data = append([]byte(nil), data...)
+        go func() {
+            done <- m.Send(data)
+        }()
+        return done
+    }
+
+    if err := m.checkSend(data); err != nil {
+        done <- err
+        return done
+    }
+    if len(data) == 0 {
+        done <- nil
+        return done
+    }
+
+    m.notifyClientSendData(m, data)
+
+    // 放入队列后由写入协程发送结果, 否则(出错或被拦截器丢弃)在这里发送
+    var queued bool
+    err := m.interceptSend(config.FrameData, data, func(data []byte) error {
+        if len(data) == 0 {
+            return nil
+        }
+        err := m.enqueueCopy(q, &sendItem{frameType: config.FrameData, data: data, done: done})
+        queued = err == nil
+        return err
+    })
+    if !queued {
+        done <- err
+    }
+    return done
+}
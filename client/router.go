@@ -0,0 +1,180 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
+    "log"
+    "runtime/debug"
+    "sync"
+)
+
+// 路由处理函数
+type RouteHandler func(c *Client, route string, data []byte)
+
+// 中间件, 包装路由处理函数
+type Middleware func(next RouteHandler) RouteHandler
+
+// 处理函数发生 panic 时调用
+type RoutePanicHandler func(c *Client, route string, v interface{})
+
+// 路由处理出错时调用, 例如 HandleValue 反序列化失败
+type RouteErrorHandler func(c *Client, route string, err error)
+
+// 默认的 panic 处理函数, 使用标准库 log 输出 panic 和调用栈
+var DefaultRoutePanicHandler RoutePanicHandler = func(c *Client, route string, v interface{}) {
+    log.Printf("ztcp: 路由 %q 的处理函数发生 panic: %v\n%s", route, v, debug.Stack())
+}
+
+// 路由器, 将 SendRoute 发送的数据按路由分发给处理函数
+type Router struct {
+    mx sync.RWMutex
+    // 只包装了路由自己的中间件
+    handlers    map[string]RouteHandler
+    fallback    RouteHandler
+    middlewares []Middleware
+    // 包装了所有中间件的处理函数, 在注册路由和添加中间件时更新, 分发时直接使用
+    chained         map[string]RouteHandler
+    chainedFallback RouteHandler
+    panicHandler    RoutePanicHandler
+    errorHandler    RouteErrorHandler
+}
+
+func NewRouter() *Router {
+    return &Router{
+        handlers:     make(map[string]RouteHandler),
+        chained:      make(map[string]RouteHandler),
+        panicHandler: DefaultRoutePanicHandler,
+    }
+}
+
+// 注册路由, middlewares 只作用于这个路由
+func (m *Router) Handle(route string, handler RouteHandler, middlewares ...Middleware) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.handlers[route] = chain(handler, middlewares)
+    m.chained[route] = chain(m.handlers[route], m.middlewares)
+}
+
+// 设置没有匹配到路由时的处理函数
+func (m *Router) HandleFallback(handler RouteHandler) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.fallback = handler
+    m.chainedFallback = nil
+    if handler != nil {
+        m.chainedFallback = chain(handler, m.middlewares)
+    }
+}
+
+// 添加作用于所有路由(包括 fallback)的中间件, 先添加的在外层
+func (m *Router) Use(middlewares ...Middleware) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.middlewares = append(m.middlewares, middlewares...)
+
+    for route, handler := range m.handlers {
+        m.chained[route] = chain(handler, m.middlewares)
+    }
+    if m.fallback != nil {
+        m.chainedFallback = chain(m.fallback, m.middlewares)
+    }
+}
+
+// 设置 panic 处理函数, 每个路由的 panic 都会被恢复, 不会影响连接
+//
+// 默认为 DefaultRoutePanicHandler, handler 为 nil 时恢复为默认
+func (m *Router) HandlePanic(handler RoutePanicHandler) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    if handler == nil {
+        handler = DefaultRoutePanicHandler
+    }
+    m.panicHandler = handler
+}
+
+// 设置路由处理出错时的处理函数
+func (m *Router) HandleError(handler RouteErrorHandler) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.errorHandler = handler
+}
+
+func (m *Router) handleError(c *Client, route string, err error) {
+    m.mx.RLock()
+    handler := m.errorHandler
+    m.mx.RUnlock()
+
+    if handler != nil {
+        handler(c, route, err)
+    }
+}
+
+// 分发数据, 没有匹配的路由并且没有设置 fallback 时丢弃数据
+func (m *Router) Dispatch(c *Client, route string, data []byte) {
+    m.mx.RLock()
+    handler, ok := m.chained[route]
+    if !ok {
+        handler = m.chainedFallback
+    }
+    panicHandler := m.panicHandler
+    m.mx.RUnlock()
+
+    if handler == nil {
+        return
+    }
+
+    defer func() {
+        if v := recover(); v != nil {
+            panicHandler(c, route, v)
+        }
+    }()
+    handler(c, route, data)
+}
+
+// 组合中间件, 第一个中间件在最外层
+func chain(handler RouteHandler, middlewares []Middleware) RouteHandler {
+    for i := len(middlewares) - 1; i >= 0; i-- {
+        handler = middlewares[i](handler)
+    }
+    return handler
+}
+
+// 向对端发送路由数据, 由对端的 Router 分发
+func (m *Client) SendRoute(route string, data []byte) error {
+    if err := m.checkSend(data); err != nil {
+        return err
+    }
+
+    ext, err := utils.RouteExt(route)
+    if err != nil {
+        return err
+    }
+
+    return m.interceptSend(config.FrameRoute, data, func(data []byte) error {
+        if q := m.getSendQueue(); q != nil {
+            return m.enqueueCopy(q, &sendItem{frameType: config.FrameRoute, ext: ext, data: data})
+        }
+        return m.writeFrame(config.FrameRoute, ext, data)
+    })
+}
+
+// 在 Router 上注册路由, 服务端的客户端共享服务端的 Router
+func (m *Client) Handle(route string, handler RouteHandler, middlewares ...Middleware) {
+    m.opts.Router.Handle(route, handler, middlewares...)
+}
+
+func (m *Client) HandleFallback(handler RouteHandler) {
+    m.opts.Router.HandleFallback(handler)
+}
+
+func (m *Client) Use(middlewares ...Middleware) {
+    m.opts.Router.Use(middlewares...)
+}
@@ -0,0 +1,87 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "context"
+    "sync"
+)
+
+// 连接的会话数据, 并发安全, 客户端关闭并且关闭观察者执行完毕后会被清空
+type Session struct {
+    mx   sync.RWMutex
+    data map[interface{}]interface{}
+}
+
+func newSession() *Session {
+    return &Session{data: make(map[interface{}]interface{})}
+}
+
+func (m *Session) Get(key interface{}) (interface{}, bool) {
+    m.mx.RLock()
+    defer m.mx.RUnlock()
+    v, ok := m.data[key]
+    return v, ok
+}
+
+func (m *Session) Set(key, value interface{}) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.data[key] = value
+}
+
+// 如果 key 不存在则设置, 返回最终的值和是否已存在
+func (m *Session) SetIfAbsent(key, value interface{}) (actual interface{}, loaded bool) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    if v, ok := m.data[key]; ok {
+        return v, true
+    }
+    m.data[key] = value
+    return value, false
+}
+
+func (m *Session) Delete(key interface{}) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    delete(m.data, key)
+}
+
+// 遍历所有数据, fn 返回 false 时停止, fn 中不能修改 Session
+func (m *Session) Range(fn func(key, value interface{}) bool) {
+    m.mx.RLock()
+    defer m.mx.RUnlock()
+    for k, v := range m.data {
+        if !fn(k, v) {
+            return
+        }
+    }
+}
+
+func (m *Session) Len() int {
+    m.mx.RLock()
+    defer m.mx.RUnlock()
+    return len(m.data)
+}
+
+func (m *Session) Clear() {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.data = make(map[interface{}]interface{})
+}
+
+// 获取会话数据
+func (m *Client) Session() *Session {
+    return m.session
+}
+
+// 获取客户端的 context, 客户端关闭时(关闭观察者执行前)被取消, 重连不会取消
+func (m *Client) Context() context.Context {
+    return m.ctx
+}
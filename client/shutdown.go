@@ -0,0 +1,96 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "context"
+    "github.com/zlyuancn/ztcp/config"
+    "sync/atomic"
+)
+
+// 优雅关闭
+//
+// 不再处理对端发来的新数据和请求, goodbye 为 true 时向对端发送告别帧,
+// 等待进行中的 ClientGetDataObserve, 请求处理和写入完成后关闭连接, ctx 结束时强制关闭并返回 ctx.Err().
+// 返回时关闭观察者已经执行完毕
+func (m *Client) Shutdown(ctx context.Context, goodbye bool) error {
+    // 在 workMx 中设置, 之后 tryBeginWork 一定失败, 之前成功的会被 waitIdle 等待
+    m.workMx.Lock()
+    atomic.StoreInt32(&m.draining, 1)
+    m.workMx.Unlock()
+    if goodbye && m.IsConnected() {
+        _ = m.writeFrame(config.FrameGoodbye, nil, nil)
+    }
+
+    err := m.waitIdle(ctx)
+    _ = m.Close()
+
+    // 强制关闭后 ClientGetDataObserve 可能仍然没有返回, 这里只能继续等待
+    <-m.closedChan
+    return err
+}
+
+// 对端是否发来了告别帧
+func (m *Client) isPeerGoodbye() bool {
+    return atomic.LoadInt32(&m.peerGoodbye) == 1
+}
+
+func (m *Client) isDraining() bool {
+    return atomic.LoadInt32(&m.draining) == 1
+}
+
+func (m *Client) beginWork() {
+    m.workMx.Lock()
+    m.workCount++
+    m.workMx.Unlock()
+}
+
+// 没有在优雅关闭时开始处理对端发来的数据, 返回 false 表示已经开始优雅关闭
+func (m *Client) tryBeginWork() bool {
+    m.workMx.Lock()
+    defer m.workMx.Unlock()
+
+    if m.isDraining() {
+        return false
+    }
+    m.workCount++
+    return true
+}
+
+func (m *Client) endWork() {
+    m.workMx.Lock()
+    defer m.workMx.Unlock()
+
+    m.workCount--
+    if m.workCount == 0 && m.workIdle != nil {
+        close(m.workIdle)
+        m.workIdle = nil
+    }
+}
+
+// 等待进行中的数据处理和写入完成
+func (m *Client) waitIdle(ctx context.Context) error {
+    m.workMx.Lock()
+    if m.workCount == 0 {
+        m.workMx.Unlock()
+        return nil
+    }
+    if m.workIdle == nil {
+        m.workIdle = make(chan struct{})
+    }
+    idle := m.workIdle
+    m.workMx.Unlock()
+
+    select {
+    case <-idle:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
@@ -0,0 +1,56 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "net"
+)
+
+// 获取 tls 连接状态, 未使用 tls 时 ok 为 false
+//
+// 握手在信任阶段完成, 在 ClientConnectObserve 中可以放心使用
+func (m *Client) ConnectionState() (state tls.ConnectionState, ok bool) {
+    conn, ok := m.conn().(*tls.Conn)
+    if !ok {
+        return state, false
+    }
+    return conn.ConnectionState(), true
+}
+
+// 获取对端已验证的证书链, 未使用 tls 或对端未提供证书时返回 nil
+func (m *Client) VerifiedChains() [][]*x509.Certificate {
+    state, ok := m.ConnectionState()
+    if !ok {
+        return nil
+    }
+    return state.VerifiedChains
+}
+
+// 获取对端证书, 第一个为对端自己的证书, 未使用 tls 或对端未提供证书时返回 nil
+func (m *Client) PeerCertificates() []*x509.Certificate {
+    state, ok := m.ConnectionState()
+    if !ok {
+        return nil
+    }
+    return state.PeerCertificates
+}
+
+// 为主动连接的客户端包装 tls, 未设置 ServerName 时使用连接地址的主机名
+func (m *Client) wrapTLS(conn net.Conn) net.Conn {
+    config := m.opts.TLSConfig
+    if config.ServerName == "" && !config.InsecureSkipVerify {
+        if host, _, err := net.SplitHostPort(m.opts.ConnectAddr); err == nil {
+            config = config.Clone()
+            config.ServerName = host
+        }
+    }
+    return tls.Client(conn, config)
+}
@@ -0,0 +1,70 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package client
+
+import (
+    "github.com/zlyuancn/zassert"
+    "reflect"
+)
+
+var clientType = reflect.TypeOf((*Client)(nil))
+
+// 使用 Serializer 序列化后发送
+func (m *Client) SendValue(v interface{}) error {
+    data, err := m.opts.Serializer.Marshal(v)
+    if err != nil {
+        return err
+    }
+    return m.Send(data)
+}
+
+// 使用 Serializer 序列化后发送路由数据
+func (m *Client) SendRouteValue(route string, v interface{}) error {
+    data, err := m.opts.Serializer.Marshal(v)
+    if err != nil {
+        return err
+    }
+    return m.SendRoute(route, data)
+}
+
+// 注册类型化的路由, handler 必须是 func(c *Client, v T) 或 func(c *Client, v *T),
+// 收到的数据会使用收到数据的 Client 的 Serializer 反序列化为 T 后调用 handler,
+// 反序列化失败时调用 HandleError 设置的函数
+func (m *Router) HandleValue(route string, handler interface{}, middlewares ...Middleware) {
+    fn := reflect.ValueOf(handler)
+    ft := fn.Type()
+    if ft.Kind() != reflect.Func || ft.NumIn() != 2 || ft.NumOut() != 0 || ft.In(0) != clientType {
+        zassert.PanicAssertError("HandleValue 的 handler 必须是 func(c *Client, v T) 或 func(c *Client, v *T)")
+    }
+
+    valueType := ft.In(1)
+    m.Handle(route, func(c *Client, route string, data []byte) {
+        var ptr reflect.Value
+        if valueType.Kind() == reflect.Ptr {
+            ptr = reflect.New(valueType.Elem())
+        } else {
+            ptr = reflect.New(valueType)
+        }
+
+        if err := c.opts.Serializer.Unmarshal(data, ptr.Interface()); err != nil {
+            m.handleError(c, route, err)
+            return
+        }
+
+        arg := ptr
+        if valueType.Kind() != reflect.Ptr {
+            arg = ptr.Elem()
+        }
+        fn.Call([]reflect.Value{reflect.ValueOf(c), arg})
+    }, middlewares...)
+}
+
+func (m *Client) HandleValue(route string, handler interface{}, middlewares ...Middleware) {
+    m.opts.Router.HandleValue(route, handler, middlewares...)
+}
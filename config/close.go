@@ -0,0 +1,81 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package config
+
+// 客户端关闭的原因
+type CloseCode int32
+
+const (
+    //本地调用了 Close
+    CloseLocal CloseCode = iota
+    //本地优雅关闭
+    CloseShutdown
+    //对端关闭了连接
+    ClosePeerClosed
+    //对端发送告别帧后关闭了连接(对端优雅关闭)
+    ClosePeerGoodbye
+    //心跳超时
+    CloseHeartbeatTimeout
+    //数据长度超过限制
+    CloseFrameTooLarge
+    //协议错误
+    CloseProtocolError
+    //网络错误
+    CloseNetworkError
+    //发送队列已满, 按 SendQueueDisconnect 策略断开
+    CloseSendQueueFull
+    //拨号失败
+    CloseDialFailed
+    //信任阶段失败, 包括超时, 认证失败和被对端拒绝
+    CloseHandshakeFailed
+    //处理数据时出错, 比如接收拦截器返回了错误
+    CloseError
+    //被服务端踢出, 服务端对这个客户端调用了 Close
+    ClosePeerKicked
+)
+
+var closeCodeNames = [...]string{
+    CloseLocal:            "本地关闭",
+    CloseShutdown:         "本地优雅关闭",
+    ClosePeerClosed:       "对端关闭",
+    ClosePeerGoodbye:      "对端优雅关闭",
+    CloseHeartbeatTimeout: "心跳超时",
+    CloseFrameTooLarge:    "数据长度超过限制",
+    CloseProtocolError:    "协议错误",
+    CloseNetworkError:     "网络错误",
+    CloseSendQueueFull:    "发送队列已满",
+    CloseDialFailed:       "拨号失败",
+    CloseHandshakeFailed:  "信任失败",
+    CloseError:            "处理数据出错",
+    ClosePeerKicked:       "被对端踢出",
+}
+
+func (m CloseCode) String() string {
+    if m >= 0 && int(m) < len(closeCodeNames) {
+        return closeCodeNames[m]
+    }
+    return "未知原因"
+}
+
+// 发起关闭的一方
+type CloseSource int32
+
+const (
+    //本地
+    CloseSourceLocal CloseSource = iota
+    //对端
+    CloseSourceRemote
+)
+
+func (m CloseSource) String() string {
+    if m == CloseSourceRemote {
+        return "对端"
+    }
+    return "本地"
+}
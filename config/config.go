@@ -14,11 +14,15 @@ import (
 
 //数据传输缓存大小
 var DefaultDataBuffSize = 1024 * 64
+//连接读取缓冲大小, 每个连接一个
+var DefaultReadBuffSize = 1024 * 4
 //一个包传输最大允许缓存大小
 var DefaultDataPackageBuffSize = 1024 * 1024 * 64
 
 // 心跳时间精度
 var DefaultHeartbeatPrecision time.Duration = 1e9
+// 时间轮的槽数量
+var DefaultTimingWheelSlots = 512
 
 // 默认信任消息
 var DefaultTrustMsg = []byte("hello ztcp")
@@ -30,4 +34,14 @@ const (
     DataClientIdLength = 8
     //数据头占用字节数
     DataHeaderLength = 4
+    //帧类型占用字节数
+    DataFrameTypeLength = 1
+    //请求id占用字节数
+    DataRequestIdLength = 8
+    //错误响应的错误码占用字节数
+    DataErrorCodeLength = 1
+    //路由长度占用字节数
+    DataRouteLength = 2
+    //ping 时间戳占用字节数
+    DataTimestampLength = 8
 )
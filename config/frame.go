@@ -0,0 +1,52 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package config
+
+// 帧类型, 位于每个非空数据包的第一个字节
+type FrameType byte
+
+const (
+    //普通数据
+    FrameData FrameType = iota
+    //请求, 之后是请求id和请求数据
+    FrameRequest
+    //响应, 之后是请求id和响应数据
+    FrameResponse
+    //错误响应, 之后是请求id, 错误码和错误信息
+    FrameErrorResponse
+    //告别, 表示对端正在关闭, 不要再发送新的数据
+    FrameGoodbye
+    //路由数据, 之后是路由和数据
+    FrameRoute
+    //ping, 之后是发送方的时间戳
+    FramePing
+    //pong, 之后是原样返回的 ping 时间戳
+    FramePong
+    //踢出, 服务端主动关闭客户端前发送, 没有数据
+    FrameKick
+)
+
+const (
+    //压缩标记, 设置时帧类型和扩展头之后的数据是压缩过的
+    FrameFlagCompressed FrameType = 0x80
+    //帧类型掩码
+    FrameTypeMask FrameType = 0x7f
+)
+
+// 错误响应的错误码, 对端可以据此还原为对应的错误
+type CallErrorCode byte
+
+const (
+    //CallHandler 返回的错误
+    CallErrorHandler CallErrorCode = iota
+    //未设置 CallHandler
+    CallErrorNoHandler
+    //正在优雅关闭, 不再处理请求
+    CallErrorShuttingDown
+)
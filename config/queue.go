@@ -0,0 +1,23 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package config
+
+// 发送队列满时的策略
+type SendQueuePolicy int32
+
+const (
+    //阻塞等待队列有空位
+    SendQueueBlock SendQueuePolicy = iota
+    //丢弃新数据
+    SendQueueDropNewest
+    //丢弃队列中最旧的数据
+    SendQueueDropOldest
+    //断开慢消费者的连接
+    SendQueueDisconnect
+)
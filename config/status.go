@@ -29,4 +29,6 @@ const (
     ClientWaitTrust
     //连接成功
     ClientConnected
+    //等待重连
+    ClientReconnecting
 )
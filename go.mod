@@ -1,5 +1,5 @@
 module github.com/zlyuancn/ztcp
 
-go 1.12
+go 1.13
 
 require github.com/zlyuancn/zassert v0.0.0-20190705030113-c03c9adae9a9
@@ -0,0 +1,65 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "errors"
+    "github.com/zlyuancn/ztcp/utils"
+    "net"
+    "time"
+)
+
+// 接受连接循环, 服务端关闭时返回 nil, 出现非临时错误时关闭服务端并返回该错误
+func (m *Server) serve(listener net.Listener) error {
+    attempt := 0
+    for m.serving(listener) {
+        conn, err := listener.Accept()
+        if err != nil {
+            err = utils.NewProtocolError("accept", err)
+            // 服务端已经关闭
+            if !m.serving(listener) {
+                return nil
+            }
+
+            // 和 net/http 一样, 对 EMFILE 等临时错误进行退避重试
+            var ne net.Error
+            if errors.As(err, &ne) && ne.Temporary() {
+                m.notifyAcceptError(err, true)
+                attempt++
+                time.Sleep(m.opts.AcceptBackoff.Delay(attempt))
+                continue
+            }
+
+            m.notifyAcceptError(err, false)
+            _ = m.Close()
+            return err
+        }
+        attempt = 0
+
+        if !m.admit(conn) {
+            continue
+        }
+        go func() {
+            defer m.untrackConn(conn)
+            m.connectedHandler(conn)
+        }()
+    }
+    return nil
+}
+
+// 检查服务端是否还在这个监听器上监听, Close 后重新 Serve 时旧的循环需要退出
+func (m *Server) serving(listener net.Listener) bool {
+    return m.IsListening() && m.listener() == listener
+}
+
+func (m *Server) notifyAcceptError(err error, temporary bool) {
+    for _, fn := range m.opts.AcceptErrorObserves {
+        fn(err, temporary)
+    }
+}
@@ -0,0 +1,168 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "errors"
+    "net"
+    "sync"
+    "testing"
+    "time"
+)
+
+type acceptError struct {
+    temporary bool
+}
+
+func (m *acceptError) Error() string   { return "accept error" }
+func (m *acceptError) Timeout() bool   { return false }
+func (m *acceptError) Temporary() bool { return m.temporary }
+
+// 按顺序返回 errs 中的错误, 用完后一直返回临时错误, 关闭后返回非临时错误
+type fakeListener struct {
+    mx      sync.Mutex
+    errs    []error
+    accepts []time.Time
+    closed  bool
+}
+
+func (m *fakeListener) Accept() (net.Conn, error) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    m.accepts = append(m.accepts, time.Now())
+    if m.closed {
+        return nil, errors.New("use of closed network connection")
+    }
+    if len(m.errs) == 0 {
+        return nil, &acceptError{temporary: true}
+    }
+    err := m.errs[0]
+    m.errs = m.errs[1:]
+    return nil, err
+}
+
+func (m *fakeListener) Close() error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.closed = true
+    return nil
+}
+
+func (m *fakeListener) Addr() net.Addr {
+    return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+}
+
+func (m *fakeListener) acceptTimes() []time.Time {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    return append([]time.Time(nil), m.accepts...)
+}
+
+type acceptErrorRecord struct {
+    err       error
+    temporary bool
+}
+
+func newAcceptTestServer(records *[]acceptErrorRecord, mx *sync.Mutex) *Server {
+    return New(
+        WithAcceptBackoff(10*time.Millisecond, 40*time.Millisecond, 2, 0),
+        WithAcceptErrorObserves(func(err error, temporary bool) {
+            mx.Lock()
+            *records = append(*records, acceptErrorRecord{err, temporary})
+            mx.Unlock()
+        }),
+    )
+}
+
+func TestServeBacksOffOnTemporaryErrors(t *testing.T) {
+    permanent := &acceptError{temporary: false}
+    listener := &fakeListener{errs: []error{
+        &acceptError{temporary: true},
+        &acceptError{temporary: true},
+        &acceptError{temporary: true},
+        permanent,
+    }}
+
+    var mx sync.Mutex
+    var records []acceptErrorRecord
+    s := newAcceptTestServer(&records, &mx)
+
+    err := s.Serve(listener)
+    if !errors.Is(err, permanent) {
+        t.Fatalf("Serve 应该返回非临时错误, 实际为 %v", err)
+    }
+    if s.IsListening() {
+        t.Fatal("出现非临时错误后服务端应该关闭")
+    }
+
+    mx.Lock()
+    defer mx.Unlock()
+    want := []bool{true, true, true, false}
+    if len(records) != len(want) {
+        t.Fatalf("AcceptErrorObserve 执行了 %d 次, 应该为 %d", len(records), len(want))
+    }
+    for i, r := range records {
+        if r.temporary != want[i] {
+            t.Errorf("第 %d 次 temporary 为 %v, 应该为 %v", i+1, r.temporary, want[i])
+        }
+    }
+
+    // 每次临时错误后按退避间隔等待: 10ms, 20ms, 40ms
+    accepts := listener.acceptTimes()
+    if len(accepts) != len(want) {
+        t.Fatalf("Accept 调用了 %d 次, 应该为 %d", len(accepts), len(want))
+    }
+    for i, delay := range []time.Duration{10, 20, 40} {
+        if d := accepts[i+1].Sub(accepts[i]); d < delay*time.Millisecond {
+            t.Errorf("第 %d 次重试前只等待了 %v, 应该至少为 %v", i+1, d, delay*time.Millisecond)
+        }
+    }
+}
+
+func TestServeReturnsNilAfterClose(t *testing.T) {
+    listener := &fakeListener{}
+
+    var mx sync.Mutex
+    var records []acceptErrorRecord
+    s := newAcceptTestServer(&records, &mx)
+
+    done := make(chan error, 1)
+    go func() {
+        done <- s.Serve(listener)
+    }()
+
+    time.Sleep(200 * time.Millisecond)
+    _ = s.Close()
+
+    select {
+    case err := <-done:
+        if err != nil {
+            t.Fatalf("Close 后 Serve 应该返回 nil, 实际为 %v", err)
+        }
+    case <-time.After(time.Second):
+        t.Fatal("Close 后 Serve 没有返回")
+    }
+
+    // 间隔最大为 40ms, 200ms 内不会超过 10 次, 没有退避时会有成千上万次
+    if n := len(listener.acceptTimes()); n > 10 {
+        t.Fatalf("200ms 内 Accept 调用了 %d 次, 没有退避", n)
+    }
+
+    mx.Lock()
+    defer mx.Unlock()
+    if len(records) == 0 {
+        t.Fatal("临时错误没有通知观察者")
+    }
+    for _, r := range records {
+        if !r.temporary {
+            t.Fatalf("Close 导致的错误不应该通知观察者: %v", r.err)
+        }
+    }
+}
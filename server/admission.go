@@ -0,0 +1,71 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "net"
+)
+
+// 对新连接进行准入检查, 被拒绝的连接会通知拒绝观察者然后关闭
+func (m *Server) admit(conn net.Conn) bool {
+    err := m.checkAccept(conn)
+    if err == nil {
+        err = m.trackConn(conn)
+    }
+    if err != nil {
+        m.notifyClientReject(conn, err)
+        _ = conn.Close()
+        return false
+    }
+    return true
+}
+
+// 检查不需要加锁的准入条件
+func (m *Server) checkAccept(conn net.Conn) error {
+    if m.opts.AcceptFilter != nil && !m.opts.AcceptFilter(conn) {
+        return ErrAcceptFiltered
+    }
+    if m.opts.AcceptRateLimiter != nil && !m.opts.AcceptRateLimiter.Allow() {
+        return ErrAcceptRateLimited
+    }
+    return nil
+}
+
+// 检查连接数限制, 调用者必须持有 mx
+func (m *Server) checkLimit(ip string) error {
+    if m.opts.MaxClients > 0 && len(m.conns) >= m.opts.MaxClients {
+        return ErrMaxClients
+    }
+    if m.opts.MaxClientsPerIP > 0 && m.ipConns[ip] >= m.opts.MaxClientsPerIP {
+        return ErrMaxClientsPerIP
+    }
+    return nil
+}
+
+func (m *Server) notifyClientReject(conn net.Conn, err error) {
+    for _, fn := range m.opts.ClientRejectObserves {
+        fn(conn, err)
+    }
+}
+
+// 获取连接的远程ip
+func remoteIP(conn net.Conn) string {
+    addr := conn.RemoteAddr()
+    if addr == nil {
+        return ""
+    }
+    if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+        return tcpAddr.IP.String()
+    }
+    host, _, err := net.SplitHostPort(addr.String())
+    if err != nil {
+        return addr.String()
+    }
+    return host
+}
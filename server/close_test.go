@@ -0,0 +1,79 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "context"
+    "github.com/zlyuancn/ztcp/client"
+    "github.com/zlyuancn/ztcp/config"
+    "testing"
+    "time"
+)
+
+func waitCloseReason(t *testing.T, reasons chan *client.CloseReason) *client.CloseReason {
+    t.Helper()
+    select {
+    case reason := <-reasons:
+        return reason
+    case <-time.After(5 * time.Second):
+        t.Fatal("关闭观察者没有执行")
+    }
+    return nil
+}
+
+func closeReasonObserve(reasons chan *client.CloseReason) client.ClientCloseObserve {
+    return func(c *client.Client, err error) {
+        reasons <- err.(*client.CloseReason)
+    }
+}
+
+// 服务端踢出客户端和客户端主动断开, 双方的关闭观察者都能区分
+func TestCloseReasonKickAndHangUp(t *testing.T) {
+    serverReasons := make(chan *client.CloseReason, 1)
+    connected := make(chan *client.Client, 1)
+    _, addr, stop := startServer(t,
+        WithClientConnectObserves(func(c *client.Client) {
+            connected <- c
+        }),
+        WithClientCloseObserves(closeReasonObserve(serverReasons)),
+    )
+    defer stop()
+
+    dial := func() (*client.Client, chan *client.CloseReason) {
+        reasons := make(chan *client.CloseReason, 1)
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        c, err := client.Dial(ctx, client.WithConnectAddr(addr), client.WithClientCloseObserves(closeReasonObserve(reasons)))
+        if err != nil {
+            t.Fatal(err)
+        }
+        return c, reasons
+    }
+
+    // 服务端踢出
+    _, clientReasons := dial()
+    _ = (<-connected).Close()
+    if r := waitCloseReason(t, clientReasons); r.Code != config.ClosePeerKicked || r.Source != config.CloseSourceRemote {
+        t.Errorf("被踢出的客户端关闭原因为 %v(%v)", r.Code, r.Source)
+    }
+    if r := waitCloseReason(t, serverReasons); r.Code != config.CloseLocal {
+        t.Errorf("服务端踢出客户端的关闭原因为 %v", r.Code)
+    }
+
+    // 客户端主动断开
+    c, clientReasons := dial()
+    <-connected
+    _ = c.Close()
+    if r := waitCloseReason(t, clientReasons); r.Code != config.CloseLocal {
+        t.Errorf("主动断开的客户端关闭原因为 %v", r.Code)
+    }
+    if r := waitCloseReason(t, serverReasons); r.Code != config.ClosePeerClosed || r.Source != config.CloseSourceRemote {
+        t.Errorf("客户端断开后服务端的关闭原因为 %v(%v)", r.Code, r.Source)
+    }
+}
@@ -0,0 +1,31 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "errors"
+)
+
+var (
+    // 服务端已经在监听
+    ErrServerListening = errors.New("服务端已经在监听")
+    // 服务端正在优雅关闭或者已经优雅关闭
+    ErrServerShutdown = errors.New("服务端已经优雅关闭")
+
+    // 以下为连接被拒绝的原因, 会传给 ClientRejectObserve
+
+    // 连接被 AcceptFilter 拒绝
+    ErrAcceptFiltered = errors.New("连接被过滤器拒绝")
+    // 超过接受连接的速率限制
+    ErrAcceptRateLimited = errors.New("超过接受连接的速率限制")
+    // 超过最大连接数
+    ErrMaxClients = errors.New("超过最大连接数")
+    // 超过单个ip的最大连接数
+    ErrMaxClientsPerIP = errors.New("超过单个ip的最大连接数")
+)
@@ -0,0 +1,164 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "github.com/zlyuancn/ztcp/client"
+)
+
+// 将客户端加入分组, 分组不存在时自动创建, 客户端关闭时会自动离开所有分组
+func (m *Server) Join(group string, c *client.Client) error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    // 关闭状态在移除客户端之前设置, 这里检查后加入的客户端一定会被 removeClient 清理
+    if c.IsClosed() {
+        return client.ErrClosed
+    }
+
+    members, ok := m.groups[group]
+    if !ok {
+        members = make(clientStorage)
+        m.groups[group] = members
+    }
+    members[c.GetId()] = c
+
+    groups, ok := m.clientGroups[c.GetId()]
+    if !ok {
+        groups = make(map[string]struct{})
+        m.clientGroups[c.GetId()] = groups
+    }
+    groups[group] = struct{}{}
+    return nil
+}
+
+// 将客户端移出分组, 分组为空时自动删除
+func (m *Server) Leave(group string, c *client.Client) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.leave(group, c.GetId())
+}
+
+// 将客户端移出所有分组
+func (m *Server) LeaveAll(c *client.Client) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.leaveAll(c.GetId())
+}
+
+func (m *Server) leave(group string, clientId uint64) {
+    if members, ok := m.groups[group]; ok {
+        delete(members, clientId)
+        if len(members) == 0 {
+            delete(m.groups, group)
+        }
+    }
+    if groups, ok := m.clientGroups[clientId]; ok {
+        delete(groups, group)
+        if len(groups) == 0 {
+            delete(m.clientGroups, clientId)
+        }
+    }
+}
+
+func (m *Server) leaveAll(clientId uint64) {
+    for group := range m.clientGroups[clientId] {
+        if members, ok := m.groups[group]; ok {
+            delete(members, clientId)
+            if len(members) == 0 {
+                delete(m.groups, group)
+            }
+        }
+    }
+    delete(m.clientGroups, clientId)
+}
+
+// 发送数据给分组内的所有客户端
+func (m *Server) SendGroup(group string, data []byte) error {
+    clients := func() clientStorage {
+        m.mx.Lock()
+        defer m.mx.Unlock()
+        return m.groups[group].Copy()
+    }()
+    m.sendClients(clients, data)
+    return nil
+}
+
+// 发送数据给除了 except 之外的所有客户端
+func (m *Server) SendExcept(data []byte, except ...*client.Client) error {
+    clients := m.copyClients()
+    for _, c := range except {
+        delete(clients, c.GetId())
+    }
+    m.sendClients(clients, data)
+    return nil
+}
+
+// 发送数据给分组内除了 except 之外的所有客户端
+func (m *Server) SendGroupExcept(group string, data []byte, except ...*client.Client) error {
+    clients := func() clientStorage {
+        m.mx.Lock()
+        defer m.mx.Unlock()
+        return m.groups[group].Copy()
+    }()
+    for _, c := range except {
+        delete(clients, c.GetId())
+    }
+    m.sendClients(clients, data)
+    return nil
+}
+
+// 获取所有分组名
+func (m *Server) Groups() []string {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    groups := make([]string, 0, len(m.groups))
+    for group := range m.groups {
+        groups = append(groups, group)
+    }
+    return groups
+}
+
+// 获取分组内的所有客户端
+func (m *Server) GroupMembers(group string) []*client.Client {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    members := m.groups[group]
+    clients := make([]*client.Client, 0, len(members))
+    for _, c := range members {
+        clients = append(clients, c)
+    }
+    return clients
+}
+
+// 获取分组内的客户端数量
+func (m *Server) GroupCount(group string) int {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    return len(m.groups[group])
+}
+
+// 检查客户端是否在分组内
+func (m *Server) InGroup(group string, c *client.Client) bool {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    _, ok := m.groups[group][c.GetId()]
+    return ok
+}
+
+// 获取客户端加入的所有分组名
+func (m *Server) ClientGroups(c *client.Client) []string {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    groups := make([]string, 0, len(m.clientGroups[c.GetId()]))
+    for group := range m.clientGroups[c.GetId()] {
+        groups = append(groups, group)
+    }
+    return groups
+}
@@ -9,16 +9,27 @@
 package server
 
 import (
+    "crypto/tls"
     "github.com/zlyuancn/ztcp/client"
+    "github.com/zlyuancn/ztcp/config"
+    "github.com/zlyuancn/ztcp/utils"
     "net"
+    "strconv"
     "time"
 )
 
+//默认网络类型
+var DefaultNetwork = "tcp"
 //初始客户端容量
 var DefaultInitClientCapacity = 1000
 //心跳检测时间
 var DefaultHeartbeatCheckTime time.Duration = 40e9
 
+//接受连接出现临时错误时的初始重试间隔
+var DefaultAcceptRetryInitialInterval time.Duration = 5e6
+//接受连接出现临时错误时的最大重试间隔
+var DefaultAcceptRetryMaxInterval time.Duration = 1e9
+
 // 保存所有已连接成功的客户端
 type clientStorage map[uint64]*client.Client
 
@@ -31,11 +42,24 @@ func (m clientStorage) Copy() clientStorage {
     return a
 }
 
+// 连接被拒绝观察者, 回调返回后连接会被关闭
+type ClientRejectObserve func(conn net.Conn, err error)
+
+// 接受连接错误观察者, temporary 为 false 时服务端会停止接受连接并关闭
+type AcceptErrorObserve func(err error, temporary bool)
+
+// 连接过滤器, 返回 false 表示拒绝连接
+type AcceptFilter func(conn net.Conn) bool
+
 type Option func(opts *Options)
 
 type Options struct {
+    // 监听器, 设置后 NewServer 不再自己创建监听器
     Listener net.Listener
-    Clients  clientStorage
+    // 网络类型, 可以是 tcp, tcp4, tcp6, unix
+    Network string
+    // 绑定地址, 设置后忽略 BindIP 和 BindPort, 网络类型为 unix 时是 socket 文件路径
+    BindAddr string
 
     BindIP   string
     BindPort int
@@ -49,14 +73,74 @@ type Options struct {
     ClientSendDataObserves []client.ClientSendDataObserve
     // 获取数据观察者
     ClientGetDataObserves []client.ClientGetDataObserve
+    // 收到 pong 观察者
+    ClientPongObserves []client.ClientPongObserve
+    // 定时向客户端发送 ping 的间隔, 0表示不定时发送
+    PingInterval time.Duration
     // 检查心跳时间
     HeartbeatCheckTime time.Duration
+    // 请求处理函数
+    CallHandler client.CallHandler
+    // 分帧编解码器
+    Codec utils.Codec
+    // tls 配置, 为 nil 时不使用 tls
+    TLSConfig *tls.Config
+    // 信任阶段的认证器
+    Authenticator client.Authenticator
+    // 优雅关闭时是否向客户端发送告别帧
+    ShutdownGoodbye bool
+    // 每个客户端的发送队列容量, 0表示不使用发送队列
+    SendQueueSize int
+    // 发送队列满时的策略
+    SendQueuePolicy config.SendQueuePolicy
+    // 接收数据时使用缓冲池, 开启后 ClientGetDataObserve, 路由处理函数和接收拦截器收到的数据在返回后会被回收
+    PooledReceive bool
+    // 每个客户端连接的读取缓冲大小
+    ReadBuffSize int
+    // 支持的压缩器, 按优先级排列, 在信任阶段和对端协商
+    Compressors []utils.Compressor
+    // 数据长度小于该值时不压缩
+    CompressThreshold int
+    // 路由器
+    Router *client.Router
+    // 发送拦截器, 第一个在最外层
+    SendInterceptors []client.Interceptor
+    // 接收拦截器, 第一个在最外层
+    ReceiveInterceptors []client.Interceptor
+    // SendValue 和 HandleValue 使用的序列化器
+    Serializer utils.Serializer
+    // 最大连接数, 包括还在信任阶段的连接, 0表示不限制
+    MaxClients int
+    // 每个远程ip的最大连接数, 0表示不限制
+    MaxClientsPerIP int
+    // 接受连接的速率限制, 为 nil 时不限制
+    AcceptRateLimiter *utils.RateLimiter
+    // 连接过滤器
+    AcceptFilter AcceptFilter
+    // 连接被拒绝观察者
+    ClientRejectObserves []ClientRejectObserve
+    // 接受连接出现临时错误时的退避策略
+    AcceptBackoff utils.Backoff
+    // 接受连接错误观察者
+    AcceptErrorObserves []AcceptErrorObserve
 }
 
 func newOptions(opts ...Option) *Options {
     opt := &Options{
+        Network:            DefaultNetwork,
         InitClientCapacity: DefaultInitClientCapacity,
         HeartbeatCheckTime: DefaultHeartbeatCheckTime,
+        Codec:              utils.DefaultCodec,
+        Authenticator:      client.NewTrustMsgAuthenticator(nil),
+        CompressThreshold:  client.DefaultCompressThreshold,
+        ReadBuffSize:       utils.ReadBuffSize,
+        Router:             client.NewRouter(),
+        Serializer:         utils.DefaultSerializer,
+        AcceptBackoff: utils.Backoff{
+            InitialInterval: DefaultAcceptRetryInitialInterval,
+            MaxInterval:     DefaultAcceptRetryMaxInterval,
+            Multiplier:      2,
+        },
     }
 
     for _, o := range opts {
@@ -65,6 +149,35 @@ func newOptions(opts ...Option) *Options {
     return opt
 }
 
+// 获取监听地址
+func (m *Options) listenAddr() string {
+    if m.BindAddr != "" {
+        return m.BindAddr
+    }
+    return net.JoinHostPort(m.BindIP, strconv.Itoa(m.BindPort))
+}
+
+// 使用已有的监听器, 可以用于 unix socket, systemd socket 激活或者其它库包装的监听器
+func WithListener(listener net.Listener) Option {
+    return func(opts *Options) {
+        opts.Listener = listener
+    }
+}
+
+// 设置网络类型, 可以是 tcp, tcp4, tcp6, unix
+func WithNetwork(network string) Option {
+    return func(opts *Options) {
+        opts.Network = network
+    }
+}
+
+// 设置绑定地址, 网络类型为 unix 时是 socket 文件路径
+func WithBindAddr(addr string) Option {
+    return func(opts *Options) {
+        opts.BindAddr = addr
+    }
+}
+
 func WithBindIP(bindip string) Option {
     return func(opts *Options) {
         opts.BindIP = bindip
@@ -107,8 +220,167 @@ func WithClientGetDataObserves(observers ...client.ClientGetDataObserve) Option
     }
 }
 
+func WithClientPongObserves(observers ...client.ClientPongObserve) Option {
+    return func(opts *Options) {
+        opts.ClientPongObserves = append(opts.ClientPongObserves, observers...)
+    }
+}
+
+// 定时向每个客户端发送 ping, 可以在服务端获取客户端的往返时间
+func WithPingInterval(interval time.Duration) Option {
+    return func(opts *Options) {
+        opts.PingInterval = interval
+    }
+}
+
 func WithHeartbeatCheckTime(checktime time.Duration) Option {
     return func(opts *Options) {
         opts.HeartbeatCheckTime = checktime
     }
 }
+
+func WithCallHandler(handler client.CallHandler) Option {
+    return func(opts *Options) {
+        opts.CallHandler = handler
+    }
+}
+
+func WithCodec(codec utils.Codec) Option {
+    return func(opts *Options) {
+        opts.Codec = codec
+    }
+}
+
+// 设置 tls 配置, 设置 ClientAuth 和 ClientCAs 可以验证客户端证书(mTLS)
+func WithTLSConfig(config *tls.Config) Option {
+    return func(opts *Options) {
+        opts.TLSConfig = config
+    }
+}
+
+func WithAuthenticator(authenticator client.Authenticator) Option {
+    return func(opts *Options) {
+        opts.Authenticator = authenticator
+    }
+}
+
+func WithShutdownGoodbye(goodbye bool) Option {
+    return func(opts *Options) {
+        opts.ShutdownGoodbye = goodbye
+    }
+}
+
+// 为每个客户端开启发送队列, 策略不是 SendQueueBlock 时 SendAll 不再为每个客户端创建协程
+func WithSendQueue(size int, policy config.SendQueuePolicy) Option {
+    return func(opts *Options) {
+        opts.SendQueueSize = size
+        opts.SendQueuePolicy = policy
+    }
+}
+
+// 接收数据时使用缓冲池, 开启后以下回调收到的数据只在回调期间有效, 需要在回调返回后使用时必须拷贝:
+// ClientGetDataObserve, 路由处理函数(包括中间件和 HandleValue 的处理函数收到的原始数据)和接收拦截器.
+// 请求和响应的数据会被拷贝, 不受影响. 在回调中调用 Send 等发送方法是安全的
+func WithPooledReceive(enable bool) Option {
+    return func(opts *Options) {
+        opts.PooledReceive = enable
+    }
+}
+
+// 设置连接读取缓冲大小, 每个连接占用一个, 帧比缓冲大时需要多次读取
+func WithReadBuffSize(size int) Option {
+    return func(opts *Options) {
+        opts.ReadBuffSize = size
+    }
+}
+
+// 设置支持的压缩器, 按优先级排列, 在信任阶段和对端协商使用双方都支持的压缩器
+func WithCompressors(compressors ...utils.Compressor) Option {
+    return func(opts *Options) {
+        opts.Compressors = append(opts.Compressors, compressors...)
+    }
+}
+
+func WithCompressThreshold(threshold int) Option {
+    return func(opts *Options) {
+        opts.CompressThreshold = threshold
+    }
+}
+
+func WithRouter(router *client.Router) Option {
+    return func(opts *Options) {
+        opts.Router = router
+    }
+}
+
+func WithSendInterceptors(interceptors ...client.Interceptor) Option {
+    return func(opts *Options) {
+        opts.SendInterceptors = append(opts.SendInterceptors, interceptors...)
+    }
+}
+
+func WithReceiveInterceptors(interceptors ...client.Interceptor) Option {
+    return func(opts *Options) {
+        opts.ReceiveInterceptors = append(opts.ReceiveInterceptors, interceptors...)
+    }
+}
+
+func WithSerializer(serializer utils.Serializer) Option {
+    return func(opts *Options) {
+        opts.Serializer = serializer
+    }
+}
+
+func WithMaxClients(max int) Option {
+    return func(opts *Options) {
+        opts.MaxClients = max
+    }
+}
+
+func WithMaxClientsPerIP(max int) Option {
+    return func(opts *Options) {
+        opts.MaxClientsPerIP = max
+    }
+}
+
+// 限制接受连接的速率, 每秒最多接受 rate 个连接, 允许瞬间接受 burst 个连接, rate <= 0 表示不限制
+func WithAcceptRate(rate float64, burst int) Option {
+    return func(opts *Options) {
+        if rate <= 0 {
+            opts.AcceptRateLimiter = nil
+            return
+        }
+        opts.AcceptRateLimiter = utils.NewRateLimiter(rate, burst)
+    }
+}
+
+// 设置连接过滤器, 可以用于实现黑白名单
+func WithAcceptFilter(filter AcceptFilter) Option {
+    return func(opts *Options) {
+        opts.AcceptFilter = filter
+    }
+}
+
+func WithClientRejectObserves(observers ...ClientRejectObserve) Option {
+    return func(opts *Options) {
+        opts.ClientRejectObserves = append(opts.ClientRejectObserves, observers...)
+    }
+}
+
+// 设置接受连接出现临时错误时的退避策略(初始间隔, 最大间隔, 间隔倍数, 随机抖动系数)
+func WithAcceptBackoff(initial, max time.Duration, multiplier, jitter float64) Option {
+    return func(opts *Options) {
+        opts.AcceptBackoff = utils.Backoff{
+            InitialInterval: initial,
+            MaxInterval:     max,
+            Multiplier:      multiplier,
+            Jitter:          jitter,
+        }
+    }
+}
+
+func WithAcceptErrorObserves(observers ...AcceptErrorObserve) Option {
+    return func(opts *Options) {
+        opts.AcceptErrorObserves = append(opts.AcceptErrorObserves, observers...)
+    }
+}
@@ -0,0 +1,63 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "context"
+    "fmt"
+    "github.com/zlyuancn/ztcp/client"
+    "github.com/zlyuancn/ztcp/config"
+    "testing"
+    "time"
+)
+
+// 开启缓冲池接收和发送队列时在 ClientGetDataObserve 中直接回显, 缓冲被回收后不能影响队列中的数据
+func TestSendQueueEchoPooledReceive(t *testing.T) {
+    const n = 2000
+
+    _, addr, stop := startServer(t,
+        WithPooledReceive(true),
+        WithSendQueue(n, config.SendQueueBlock),
+        WithClientGetDataObserves(func(c *client.Client, data []byte) {
+            _ = c.Send(data)
+        }),
+    )
+    defer stop()
+
+    echoes := make(chan string, n)
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    c, err := client.Dial(ctx,
+        client.WithConnectAddr(addr),
+        client.WithClientGetDataObserves(func(c *client.Client, data []byte) {
+            echoes <- string(data)
+        }),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer c.Close()
+
+    for i := 0; i < n; i++ {
+        if err := c.Send([]byte(fmt.Sprintf("message-%06d", i))); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    for i := 0; i < n; i++ {
+        select {
+        case echo := <-echoes:
+            if want := fmt.Sprintf("message-%06d", i); echo != want {
+                t.Fatalf("第 %d 个回显为 %q, 应该为 %q", i, echo, want)
+            }
+        case <-ctx.Done():
+            t.Fatalf("只收到了 %d 个回显", i)
+        }
+    }
+}
@@ -0,0 +1,66 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "github.com/zlyuancn/ztcp/client"
+)
+
+// 根据客户端id获取已连接的客户端
+func (m *Server) GetClient(clientId uint64) (*client.Client, bool) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    c, ok := m.clients[clientId]
+    return c, ok
+}
+
+// 获取已连接的客户端数量
+func (m *Server) Count() int {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    return len(m.clients)
+}
+
+// 获取所有已连接的客户端的快照
+func (m *Server) Snapshot() []*client.Client {
+    clients := m.copyClients()
+    a := make([]*client.Client, 0, len(clients))
+    for _, c := range clients {
+        a = append(a, c)
+    }
+    return a
+}
+
+// 遍历所有已连接的客户端, fn 返回 false 时停止
+//
+// 遍历的是调用时的快照, fn 中可以调用 Server 的其它方法
+func (m *Server) Range(fn func(c *client.Client) bool) {
+    for _, c := range m.copyClients() {
+        if !fn(c) {
+            return
+        }
+    }
+}
+
+// 获取所有满足条件的客户端
+func (m *Server) Filter(fn func(c *client.Client) bool) []*client.Client {
+    var a []*client.Client
+    for _, c := range m.copyClients() {
+        if fn(c) {
+            a = append(a, c)
+        }
+    }
+    return a
+}
+
+func (m *Server) copyClients() clientStorage {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    return m.clients.Copy()
+}
@@ -0,0 +1,118 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "context"
+    "github.com/zlyuancn/ztcp/client"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// 并发连接, 关闭和广播, 所有连接关闭后注册表必须为空
+func TestRegistryConcurrentConnectClose(t *testing.T) {
+    const clients = 50
+    const rounds = 5
+
+    var s *Server
+    var connected, closed, missing, stale int32
+    s, addr, stop := startServer(t,
+        WithClientConnectObserves(func(c *client.Client) {
+            atomic.AddInt32(&connected, 1)
+            // 用户的连接观察者中一定能看到这个客户端
+            if _, ok := s.GetClient(c.GetId()); !ok {
+                atomic.AddInt32(&missing, 1)
+            }
+        }),
+        WithClientCloseObserves(func(c *client.Client, err error) {
+            atomic.AddInt32(&closed, 1)
+            // 用户的关闭观察者中已经看不到这个客户端
+            if _, ok := s.GetClient(c.GetId()); ok {
+                atomic.AddInt32(&stale, 1)
+            }
+        }),
+    )
+    defer stop()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    var wg sync.WaitGroup
+    for i := 0; i < clients; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            for j := 0; j < rounds; j++ {
+                c, err := client.Dial(ctx, client.WithConnectAddr(addr))
+                if err != nil {
+                    t.Error(err)
+                    return
+                }
+                _ = s.SendAll([]byte("hello"))
+                _ = c.Send([]byte("world"))
+                // 一半由客户端关闭, 一半由服务端关闭
+                if (i+j)%2 == 0 {
+                    _ = c.Close()
+                } else {
+                    s.Range(func(sc *client.Client) bool {
+                        if sc.GetId() == c.GetId() {
+                            _ = sc.Close()
+                        }
+                        return true
+                    })
+                    _ = c.Close()
+                }
+            }
+        }(i)
+    }
+
+    // 同时广播和读取注册表
+    done := make(chan struct{})
+    go func() {
+        for {
+            select {
+            case <-done:
+                return
+            default:
+            }
+            _ = s.SendAll([]byte("broadcast"))
+            _ = s.Snapshot()
+            _ = s.Count()
+        }
+    }()
+    wg.Wait()
+    close(done)
+
+    // 服务端发现连接断开是异步的, 关闭观察者在移出注册表之后执行
+    deadline := time.Now().Add(5 * time.Second)
+    for (s.Count() != 0 || atomic.LoadInt32(&closed) != clients*rounds) && time.Now().Before(deadline) {
+        time.Sleep(10 * time.Millisecond)
+    }
+    if n := s.Count(); n != 0 {
+        t.Fatalf("所有连接关闭后 Count() = %d", n)
+    }
+    if n := len(s.Snapshot()); n != 0 {
+        t.Fatalf("所有连接关闭后 Snapshot() 长度为 %d", n)
+    }
+
+    if n := atomic.LoadInt32(&connected); n != clients*rounds {
+        t.Errorf("连接观察者执行了 %d 次, 应该为 %d", n, clients*rounds)
+    }
+    if n := atomic.LoadInt32(&closed); n != clients*rounds {
+        t.Errorf("关闭观察者执行了 %d 次, 应该为 %d", n, clients*rounds)
+    }
+    if n := atomic.LoadInt32(&missing); n != 0 {
+        t.Errorf("%d 个客户端在连接观察者中不在注册表中", n)
+    }
+    if n := atomic.LoadInt32(&stale); n != 0 {
+        t.Errorf("%d 个客户端在关闭观察者中仍然在注册表中", n)
+    }
+}
@@ -0,0 +1,40 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "github.com/zlyuancn/ztcp/client"
+)
+
+// 注册路由, 所有客户端共享服务端的 Router
+func (m *Server) Handle(route string, handler client.RouteHandler, middlewares ...client.Middleware) {
+    m.opts.Router.Handle(route, handler, middlewares...)
+}
+
+func (m *Server) HandleFallback(handler client.RouteHandler) {
+    m.opts.Router.HandleFallback(handler)
+}
+
+func (m *Server) Use(middlewares ...client.Middleware) {
+    m.opts.Router.Use(middlewares...)
+}
+
+// 注册类型化的路由, 见 client.Router.HandleValue
+func (m *Server) HandleValue(route string, handler interface{}, middlewares ...client.Middleware) {
+    m.opts.Router.HandleValue(route, handler, middlewares...)
+}
+
+// 使用 Serializer 序列化后发送给所有客户端
+func (m *Server) SendValue(v interface{}) error {
+    data, err := m.opts.Serializer.Marshal(v)
+    if err != nil {
+        return err
+    }
+    return m.SendAll(data)
+}
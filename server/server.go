@@ -9,7 +9,8 @@
 package server
 
 import (
-    "fmt"
+    "context"
+    "crypto/tls"
     "github.com/zlyuancn/ztcp/client"
     "github.com/zlyuancn/ztcp/config"
     "net"
@@ -21,40 +22,106 @@ type Server struct {
     status config.ServerStatus
     opts   *Options
     mx     sync.Mutex
+
+    // 所有已接受的连接, 包括还在信任阶段的连接
+    conns  map[net.Conn]struct{}
+    connWg sync.WaitGroup
+    // 每个远程ip的连接数
+    ipConns map[string]int
+    // 不为 nil 表示正在优雅关闭
+    shutdownCtx context.Context
+
+    // 已连接的客户端
+    clients clientStorage
+
+    // 分组名 => 分组内的客户端
+    groups map[string]clientStorage
+    // 客户端id => 加入的分组名
+    clientGroups map[uint64]map[string]struct{}
 }
 
+// 创建服务端并开始监听, 设置了 Listener 时使用它, 否则根据 Network 和绑定地址创建监听器
 func NewServer(opts ...Option) (*Server, error) {
-    options := newOptions(opts...)
+    server := New(opts...)
+
+    listener := server.opts.Listener
+    if listener == nil {
+        var err error
+        listener, err = net.Listen(server.opts.Network, server.opts.listenAddr())
+        if err != nil {
+            return nil, err
+        }
+    }
 
-    listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", options.BindIP, options.BindPort))
+    listener, err := server.start(listener)
     if err != nil {
         return nil, err
     }
+    go func() {
+        _ = server.serve(listener)
+    }()
+    return server, nil
+}
+
+// 创建服务端但是不监听, 需要调用 Serve 在监听器上运行
+func New(opts ...Option) *Server {
+    options := newOptions(opts...)
 
     server := &Server{
-        status: config.ServerListening,
-        opts:   options,
+        status:  config.ServerClosed,
+        opts:    options,
+        conns:   make(map[net.Conn]struct{}),
+        ipConns: make(map[string]int),
+
+        clients:      make(clientStorage, options.InitClientCapacity),
+        groups:       make(map[string]clientStorage),
+        clientGroups: make(map[uint64]map[string]struct{}),
     }
 
-    options.Listener = listener
-    options.Clients = make(clientStorage, options.InitClientCapacity)
     options.ClientConnectObserves = append([]client.ClientConnectObserve{func(c *client.Client) {
         server.addClient(c)
     }}, options.ClientConnectObserves...)
     options.ClientCloseObserves = append([]client.ClientCloseObserve{func(c *client.Client, err error) {
         server.removeClient(c)
     }}, options.ClientCloseObserves...)
+    return server
+}
 
-    go func(m *Server) {
-        for m.IsListening() {
-            conn, err := listener.Accept()
-            if err != nil {
-                continue
-            }
-            go m.connectedHandler(conn)
-        }
-    }(server)
-    return server, nil
+// 在监听器上接受连接, 设置了 TLSConfig 时会包装为 tls 监听器
+//
+// 会阻塞直到服务端关闭, 调用 Close 或 Shutdown 后返回 nil, 监听器出现非临时错误时返回该错误
+func (m *Server) Serve(listener net.Listener) error {
+    listener, err := m.start(listener)
+    if err != nil {
+        return err
+    }
+    return m.serve(listener)
+}
+
+// 设置监听器并切换为监听状态
+func (m *Server) start(listener net.Listener) (net.Listener, error) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if m.IsListening() {
+        return nil, ErrServerListening
+    }
+    if m.shutdownCtx != nil {
+        return nil, ErrServerShutdown
+    }
+
+    if m.opts.TLSConfig != nil {
+        listener = tls.NewListener(listener, m.opts.TLSConfig)
+    }
+    m.opts.Listener = listener
+    atomic.StoreInt32((*int32)(&m.status), int32(config.ServerListening))
+    return listener, nil
+}
+
+func (m *Server) listener() net.Listener {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    return m.opts.Listener
 }
 
 func (m *Server) connectedHandler(conn net.Conn) {
@@ -65,6 +132,20 @@ func (m *Server) connectedHandler(conn net.Conn) {
         client.WithClientCloseObserves(m.opts.ClientCloseObserves...),
         client.WithClientSendDataObserves(m.opts.ClientSendDataObserves...),
         client.WithClientGetDataObserves(m.opts.ClientGetDataObserves...),
+        client.WithClientPongObserves(m.opts.ClientPongObserves...),
+        client.WithPingInterval(m.opts.PingInterval),
+        client.WithCallHandler(m.opts.CallHandler),
+        client.WithCodec(m.opts.Codec),
+        client.WithAuthenticator(m.opts.Authenticator),
+        client.WithSendQueue(m.opts.SendQueueSize, m.opts.SendQueuePolicy),
+        client.WithPooledReceive(m.opts.PooledReceive),
+        client.WithReadBuffSize(m.opts.ReadBuffSize),
+        client.WithCompressors(m.opts.Compressors...),
+        client.WithCompressThreshold(m.opts.CompressThreshold),
+        client.WithRouter(m.opts.Router),
+        client.WithSendInterceptors(m.opts.SendInterceptors...),
+        client.WithReceiveInterceptors(m.opts.ReceiveInterceptors...),
+        client.WithSerializer(m.opts.Serializer),
     )
 }
 
@@ -73,7 +154,11 @@ func (m *Server) Options() *Options {
 }
 
 func (m *Server) Addr() net.Addr {
-    return m.opts.Listener.Addr()
+    listener := m.listener()
+    if listener == nil {
+        return nil
+    }
+    return listener.Addr()
 }
 
 func (m *Server) Status() config.ServerStatus {
@@ -89,19 +174,18 @@ func (m *Server) IsListening() bool {
 }
 
 func (m *Server) Close() error {
-    err := m.opts.Listener.Close()
-    if err == nil {
-        atomic.StoreInt32((*int32)(&m.status), int32(config.ServerClosed))
+    // 先修改状态, 接受连接循环才能区分主动关闭和监听器出错
+    atomic.StoreInt32((*int32)(&m.status), int32(config.ServerClosed))
+    listener := m.listener()
+    if listener == nil {
+        return nil
     }
-    return err
+    return listener.Close()
 }
 
+// 关闭所有客户端, 客户端的关闭原因为 ClosePeerKicked
 func (m *Server) CloseAllClient() (err error) {
-    clients :=func() clientStorage{
-        m.mx.Lock()
-        defer m.mx.Unlock()
-        return m.opts.Clients.Copy()
-    }()
+    clients := m.copyClients()
 
     for clientid, c := range clients {
         go func(clientId uint64, c *client.Client) {
@@ -112,32 +196,46 @@ func (m *Server) CloseAllClient() (err error) {
 }
 
 func (m *Server) SendAll(data []byte) (err error) {
-    clients :=func() clientStorage{
-        m.mx.Lock()
-        defer m.mx.Unlock()
-        return m.opts.Clients.Copy()
-    }()
+    clients := m.copyClients()
+    m.sendClients(clients, data)
+    return nil
+}
+
+func (m *Server) sendClients(clients clientStorage, data []byte) {
+    // 有发送队列且队列满时不阻塞, Send 只是放入队列或者按策略处理, 可以直接在这里发送.
+    // SendQueueBlock 策略下一个客户端的队列满了会阻塞, 所以和没有发送队列时一样每个客户端使用一个协程
+    if m.opts.SendQueueSize > 0 && m.opts.SendQueuePolicy != config.SendQueueBlock {
+        for _, c := range clients {
+            _ = c.Send(data)
+        }
+        return
+    }
 
     for clientid, c := range clients {
         go func(clientId uint64, c *client.Client) {
             _ = c.Send(data)
         }(clientid, c)
     }
-    return nil
 }
 
+// 在连接观察者中同步执行, 且排在用户的观察者之前, 用户的观察者中一定能看到这个客户端
 func (m *Server) addClient(c *client.Client) {
-    go func() {
-        m.mx.Lock()
-        defer m.mx.Unlock()
-        m.opts.Clients[c.GetId()] = c
-    }()
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    m.clients[c.GetId()] = c
+
+    // 优雅关闭开始后才连接成功的客户端
+    if m.shutdownCtx != nil {
+        go func(ctx context.Context) {
+            _ = c.Shutdown(ctx, m.opts.ShutdownGoodbye)
+        }(m.shutdownCtx)
+    }
 }
 
+// 在关闭观察者中同步执行, 且排在用户的观察者之前, 用户的观察者中已经看不到这个客户端
 func (m *Server) removeClient(c *client.Client) {
-    go func() {
-        m.mx.Lock()
-        defer m.mx.Unlock()
-        delete(m.opts.Clients, c.GetId())
-    }()
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    delete(m.clients, c.GetId())
+    m.leaveAll(c.GetId())
 }
@@ -0,0 +1,37 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "net"
+    "testing"
+)
+
+// 在随机端口上启动服务端, 返回服务端, 连接地址和关闭函数
+func startServer(t testing.TB, opts ...Option) (*Server, string, func()) {
+    t.Helper()
+
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    s := New(opts...)
+    done := make(chan error, 1)
+    go func() {
+        done <- s.Serve(listener)
+    }()
+    stop := func() {
+        _ = s.Close()
+        if err := <-done; err != nil {
+            t.Errorf("Serve 返回了错误: %v", err)
+        }
+    }
+    return s, listener.Addr().String(), stop
+}
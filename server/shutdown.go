@@ -0,0 +1,97 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "context"
+    "github.com/zlyuancn/ztcp/client"
+    "github.com/zlyuancn/ztcp/config"
+    "net"
+    "sync/atomic"
+)
+
+// 优雅关闭
+//
+// 停止接受新连接, 对所有客户端执行 client.Shutdown (是否发送告别帧由 ShutdownGoodbye 决定),
+// ctx 结束时强制关闭剩余的连接并返回 ctx.Err(). 返回时所有客户端的关闭观察者已经执行完毕
+func (m *Server) Shutdown(ctx context.Context) error {
+    clients, err := func() (clientStorage, error) {
+        m.mx.Lock()
+        defer m.mx.Unlock()
+
+        if m.shutdownCtx != nil {
+            return nil, nil
+        }
+        m.shutdownCtx = ctx
+        atomic.StoreInt32((*int32)(&m.status), int32(config.ServerClosed))
+        if m.opts.Listener == nil {
+            return m.clients.Copy(), nil
+        }
+        return m.clients.Copy(), m.opts.Listener.Close()
+    }()
+
+    for _, c := range clients {
+        go func(c *client.Client) {
+            _ = c.Shutdown(ctx, m.opts.ShutdownGoodbye)
+        }(c)
+    }
+
+    done := make(chan struct{})
+    go func() {
+        m.connWg.Wait()
+        close(done)
+    }()
+
+    select {
+    case <-done:
+        return err
+    case <-ctx.Done():
+    }
+
+    // 强制关闭剩余的连接, 包括还在信任阶段的连接
+    func() {
+        m.mx.Lock()
+        defer m.mx.Unlock()
+        for conn := range m.conns {
+            _ = conn.Close()
+        }
+    }()
+
+    <-done
+    return ctx.Err()
+}
+
+// 记录一个已接受的连接, 正在优雅关闭或者超过连接数限制时返回错误
+func (m *Server) trackConn(conn net.Conn) error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if m.shutdownCtx != nil {
+        return ErrServerShutdown
+    }
+    ip := remoteIP(conn)
+    if err := m.checkLimit(ip); err != nil {
+        return err
+    }
+    m.conns[conn] = struct{}{}
+    m.ipConns[ip]++
+    m.connWg.Add(1)
+    return nil
+}
+
+func (m *Server) untrackConn(conn net.Conn) {
+    ip := remoteIP(conn)
+    m.mx.Lock()
+    delete(m.conns, conn)
+    if m.ipConns[ip]--; m.ipConns[ip] <= 0 {
+        delete(m.ipConns, ip)
+    }
+    m.mx.Unlock()
+    m.connWg.Done()
+}
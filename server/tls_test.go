@@ -0,0 +1,175 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "context"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/tls"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "github.com/zlyuancn/ztcp/client"
+    "math/big"
+    "net"
+    "testing"
+    "time"
+)
+
+type testCert struct {
+    cert *x509.Certificate
+    key  *ecdsa.PrivateKey
+}
+
+func (m *testCert) tlsCertificate() tls.Certificate {
+    return tls.Certificate{Certificate: [][]byte{m.cert.Raw}, PrivateKey: m.key, Leaf: m.cert}
+}
+
+// 生成证书, parent 为 nil 时生成自签名的 CA 证书
+func newTestCert(t *testing.T, name string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+    t.Helper()
+
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    template := &x509.Certificate{
+        SerialNumber: big.NewInt(time.Now().UnixNano()),
+        Subject:      pkix.Name{CommonName: name},
+        NotBefore:    time.Now().Add(-time.Hour),
+        NotAfter:     time.Now().Add(time.Hour),
+        KeyUsage:     x509.KeyUsageDigitalSignature,
+    }
+    signer, signerKey := template, key
+    if parent == nil {
+        template.IsCA = true
+        template.BasicConstraintsValid = true
+        template.KeyUsage |= x509.KeyUsageCertSign
+    } else {
+        template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+        template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+        signer, signerKey = parent.cert, parent.key
+    }
+
+    raw, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+    cert, err := x509.ParseCertificate(raw)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return &testCert{cert: cert, key: key}
+}
+
+type testPKI struct {
+    pool   *x509.CertPool
+    server *testCert
+    client *testCert
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+    ca := newTestCert(t, "ztcp test ca", nil, 0)
+    pool := x509.NewCertPool()
+    pool.AddCert(ca.cert)
+    return &testPKI{
+        pool:   pool,
+        server: newTestCert(t, "ztcp server", ca, x509.ExtKeyUsageServerAuth),
+        client: newTestCert(t, "ztcp client", ca, x509.ExtKeyUsageClientAuth),
+    }
+}
+
+func (m *testPKI) serverConfig() *tls.Config {
+    return &tls.Config{
+        Certificates: []tls.Certificate{m.server.tlsCertificate()},
+        ClientAuth:   tls.RequireAndVerifyClientCert,
+        ClientCAs:    m.pool,
+    }
+}
+
+func TestMutualTLS(t *testing.T) {
+    pki := newTestPKI(t)
+
+    type peer struct {
+        certs  []*x509.Certificate
+        chains [][]*x509.Certificate
+    }
+    serverPeer := make(chan peer, 1)
+    _, addr, stop := startServer(t,
+        WithTLSConfig(pki.serverConfig()),
+        WithClientConnectObserves(func(c *client.Client) {
+            serverPeer <- peer{c.PeerCertificates(), c.VerifiedChains()}
+        }),
+    )
+    defer stop()
+
+    clientPeer := make(chan peer, 1)
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    c, err := client.Dial(ctx,
+        client.WithConnectAddr(addr),
+        client.WithTLSConfig(&tls.Config{
+            Certificates: []tls.Certificate{pki.client.tlsCertificate()},
+            RootCAs:      pki.pool,
+        }),
+        client.WithClientConnectObserves(func(c *client.Client) {
+            clientPeer <- peer{c.PeerCertificates(), c.VerifiedChains()}
+        }),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer c.Close()
+
+    check := func(side string, p peer, want *testCert) {
+        if len(p.certs) == 0 || !p.certs[0].Equal(want.cert) {
+            t.Errorf("%s: PeerCertificates 不是对端证书: %v", side, p.certs)
+        }
+        if len(p.chains) == 0 || len(p.chains[0]) != 2 || !p.chains[0][0].Equal(want.cert) {
+            t.Errorf("%s: VerifiedChains 不正确: %v", side, p.chains)
+        }
+    }
+    check("服务端", <-serverPeer, pki.client)
+    check("客户端", <-clientPeer, pki.server)
+}
+
+func TestMutualTLSRejectsClientWithoutCert(t *testing.T) {
+    pki := newTestPKI(t)
+
+    connected := make(chan struct{}, 1)
+    _, addr, stop := startServer(t,
+        WithTLSConfig(pki.serverConfig()),
+        WithClientConnectObserves(func(c *client.Client) {
+            connected <- struct{}{}
+        }),
+    )
+    defer stop()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    c, err := client.Dial(ctx,
+        client.WithConnectAddr(addr),
+        client.WithTLSConfig(&tls.Config{RootCAs: pki.pool}),
+    )
+    if err == nil {
+        _ = c.Close()
+        t.Fatal("没有证书的客户端连接成功了")
+    }
+    if ctx.Err() != nil {
+        t.Fatalf("没有证书的客户端应该被立即拒绝, 实际等待到了超时: %v", err)
+    }
+
+    select {
+    case <-connected:
+        t.Fatal("服务端接受了没有证书的客户端")
+    case <-time.After(100 * time.Millisecond):
+    }
+}
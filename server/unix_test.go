@@ -0,0 +1,56 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package server
+
+import (
+    "context"
+    "github.com/zlyuancn/ztcp/client"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+func TestUnixSocket(t *testing.T) {
+    dir, err := ioutil.TempDir("", "ztcp")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+    path := filepath.Join(dir, "ztcp.sock")
+
+    s, err := NewServer(
+        WithNetwork("unix"),
+        WithBindAddr(path),
+        WithCallHandler(func(c *client.Client, data []byte) ([]byte, error) {
+            return data, nil
+        }),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer s.Close()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    c, err := client.Dial(ctx, client.WithNetwork("unix"), client.WithConnectAddr(path))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer c.Close()
+
+    resp, err := c.Call(ctx, []byte("hello"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if string(resp) != "hello" {
+        t.Fatalf("响应为 %q", resp)
+    }
+}
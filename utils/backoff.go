@@ -0,0 +1,50 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "math"
+    "math/rand"
+    "time"
+)
+
+// 指数退避
+type Backoff struct {
+    // 初始间隔
+    InitialInterval time.Duration
+    // 最大间隔
+    MaxInterval time.Duration
+    // 每次失败后间隔的倍数
+    Multiplier float64
+    // 随机抖动系数(0~1), 实际间隔在 [d*(1-Jitter), d*(1+Jitter)] 之间
+    Jitter float64
+}
+
+// 获取第 attempt 次(从1开始)重试前应该等待的时间
+func (m *Backoff) Delay(attempt int) time.Duration {
+    if attempt < 1 {
+        attempt = 1
+    }
+
+    multiplier := m.Multiplier
+    if multiplier < 1 {
+        multiplier = 1
+    }
+
+    d := float64(m.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+    if m.MaxInterval > 0 && d > float64(m.MaxInterval) {
+        d = float64(m.MaxInterval)
+    }
+
+    if m.Jitter > 0 {
+        delta := d * m.Jitter
+        d = d - delta + rand.Float64()*2*delta
+    }
+    return time.Duration(d)
+}
@@ -0,0 +1,64 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "math/bits"
+    "sync"
+    "unsafe"
+)
+
+const (
+    // 最小的缓冲级别 512 bytes
+    minBufferShift = 9
+    // 最大的缓冲级别 64M, 更大的缓冲不会放入缓冲池
+    maxBufferShift = 26
+)
+
+// 按大小分级的缓冲池, 每一级的容量为2的幂
+//
+// 池中存放缓冲第一个字节的指针, unsafe.Pointer 放入接口时不需要分配内存
+var bufferPools [maxBufferShift - minBufferShift + 1]sync.Pool
+
+// 获取能容纳 size 的缓冲级别, 超过最大级别返回 -1
+func bufferClass(size int) int {
+    if size <= 1<<minBufferShift {
+        return 0
+    }
+    shift := bits.Len(uint(size - 1))
+    if shift > maxBufferShift {
+        return -1
+    }
+    return shift - minBufferShift
+}
+
+// 从缓冲池获取一个长度为 size 的缓冲, 使用完毕后应该调用 PutBuffer 回收
+func GetBuffer(size int) []byte {
+    class := bufferClass(size)
+    if class < 0 {
+        return make([]byte, size)
+    }
+
+    capacity := 1 << uint(class+minBufferShift)
+    if p, ok := bufferPools[class].Get().(unsafe.Pointer); ok {
+        return (*[1 << maxBufferShift]byte)(p)[:size:capacity]
+    }
+    return make([]byte, size, capacity)
+}
+
+// 回收一个由 GetBuffer 获取的缓冲, 回收后不能再使用
+func PutBuffer(b []byte) {
+    class := bufferClass(cap(b))
+    // 不是由 GetBuffer 分配的缓冲
+    if class < 0 || cap(b) != 1<<uint(class+minBufferShift) {
+        return
+    }
+
+    bufferPools[class].Put(unsafe.Pointer(&b[:1][0]))
+}
@@ -0,0 +1,47 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "testing"
+)
+
+func TestGetBuffer(t *testing.T) {
+    for _, size := range []int{0, 1, 512, 513, 4096, 100000} {
+        b := GetBuffer(size)
+        if len(b) != size {
+            t.Fatalf("GetBuffer(%d) 长度为 %d", size, len(b))
+        }
+        if class := bufferClass(size); cap(b) != 1<<uint(class+minBufferShift) {
+            t.Fatalf("GetBuffer(%d) 容量为 %d", size, cap(b))
+        }
+        PutBuffer(b)
+
+        // 回收后再次获取的缓冲仍然满足长度和容量
+        b = GetBuffer(size)
+        if len(b) != size || cap(b) < size {
+            t.Fatalf("回收后 GetBuffer(%d) 长度为 %d, 容量为 %d", size, len(b), cap(b))
+        }
+        PutBuffer(b)
+    }
+
+    // 不是由 GetBuffer 分配的缓冲会被忽略
+    PutBuffer(make([]byte, 1000))
+    PutBuffer(nil)
+    if b := GetBuffer(1000); cap(b) != 1024 {
+        t.Fatalf("GetBuffer(1000) 容量为 %d", cap(b))
+    }
+}
+
+func BenchmarkBufferPool(b *testing.B) {
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        PutBuffer(GetBuffer(4096))
+    }
+}
@@ -0,0 +1,410 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "io"
+    "math"
+    "net"
+)
+
+// 帧读取器, 每个连接一个, 不要求并发安全
+type FrameReader interface {
+    // 读取一个完整的帧, 返回的数据不包含帧头, 空帧(心跳)返回 nil
+    ReadFrame() ([]byte, error)
+    // 和 ReadFrame 相同, 但是数据存放在 GetBuffer 获取的缓冲中, 使用完毕后应该调用 PutBuffer 回收
+    ReadPooledFrame() ([]byte, error)
+}
+
+// 帧写入器, 每个连接一个, 调用者负责加锁
+type FrameWriter interface {
+    // 将多段数据作为一个帧写入, 不传入数据表示写入一个空帧(心跳)
+    //
+    // 帧头和数据(以及之前缓冲的帧)使用一次 writev 写入
+    WriteFrame(parts ...[]byte) error
+    // 将一个帧加入缓冲, 调用 Flush 时一次写入
+    BufferFrame(parts ...[]byte) error
+    // 将所有缓冲的帧使用一次 writev 写入
+    Flush() error
+}
+
+// 分帧编解码器, 决定数据在连接上如何分帧
+type Codec interface {
+    // 内置的编解码器在 r 不是 *bufio.Reader 时会使用 ReadBuffSize 大小的缓冲包装
+    NewFrameReader(r io.Reader) FrameReader
+    NewFrameWriter(w io.Writer) FrameWriter
+}
+
+var (
+    // 2字节大端长度头
+    Uint16BECodec Codec = &lengthCodec{size: 2, order: binary.BigEndian}
+    // 2字节小端长度头
+    Uint16LECodec Codec = &lengthCodec{size: 2, order: binary.LittleEndian}
+    // 4字节大端长度头
+    Uint32BECodec Codec = &lengthCodec{size: 4, order: binary.BigEndian}
+    // 4字节小端长度头
+    Uint32LECodec Codec = &lengthCodec{size: 4, order: binary.LittleEndian}
+    // 8字节大端长度头
+    Uint64BECodec Codec = &lengthCodec{size: 8, order: binary.BigEndian}
+    // 8字节小端长度头
+    Uint64LECodec Codec = &lengthCodec{size: 8, order: binary.LittleEndian}
+    // uvarint 长度头
+    UvarintCodec Codec = uvarintCodec{}
+)
+
+// 默认编解码器, 4字节大端长度头
+var DefaultCodec = Uint32BECodec
+
+// 以分隔符结尾的编解码器
+//
+// 帧中包含二进制的帧类型, 请求id和时间戳, 所以数据中的分隔符和转义字节会被转义为 [转义字节, b^0x20],
+// 转义字节为 0x7d, 分隔符为 0x7d 或 0x5d 时使用 0x1b
+func NewDelimiterCodec(delim byte) Codec {
+    esc := byte(0x7d)
+    if delim == esc || delim == esc^delimiterEscapeMask {
+        esc = 0x1b
+    }
+    return delimiterCodec{delim: delim, esc: esc}
+}
+
+func partsLength(parts [][]byte) int {
+    var length int
+    for _, p := range parts {
+        length += len(p)
+    }
+    return length
+}
+
+func appendParts(bufs net.Buffers, parts [][]byte) net.Buffers {
+    for _, p := range parts {
+        if len(p) > 0 {
+            bufs = append(bufs, p)
+        }
+    }
+    return bufs
+}
+
+// 包装为带缓冲的读取器, 已经是 *bufio.Reader 时直接使用
+func bufferedReader(r io.Reader) *bufio.Reader {
+    if br, ok := r.(*bufio.Reader); ok {
+        return br
+    }
+    return bufio.NewReaderSize(r, ReadBuffSize)
+}
+
+func makeBuffer(size int) []byte {
+    return make([]byte, size)
+}
+
+// 读取数据填满 buff, 失败时回收 buff
+func readFull(r io.Reader, buff []byte, pooled bool) ([]byte, error) {
+    if _, err := io.ReadFull(r, buff); err != nil {
+        if pooled {
+            PutBuffer(buff)
+        }
+        return nil, err
+    }
+    return buff, nil
+}
+
+func frameTooLargeError(size uint64) error {
+    return &FrameTooLargeError{Size: size, Limit: uint64(DataPackageBuffSize)}
+}
+
+// 使用 net.Buffers 写入的帧写入器, 底层为 *net.TCPConn 时会使用 writev
+type buffersFrameWriter struct {
+    w io.Writer
+    // 将一个帧编码后追加到 bufs, 出错时返回原来的 bufs
+    encode func(bufs net.Buffers, parts [][]byte) (net.Buffers, error)
+    bufs   net.Buffers
+}
+
+func (m *buffersFrameWriter) WriteFrame(parts ...[]byte) error {
+    if err := m.BufferFrame(parts...); err != nil {
+        return err
+    }
+    return m.Flush()
+}
+
+func (m *buffersFrameWriter) BufferFrame(parts ...[]byte) (err error) {
+    m.bufs, err = m.encode(m.bufs, parts)
+    return err
+}
+
+func (m *buffersFrameWriter) Flush() error {
+    if len(m.bufs) == 0 {
+        return nil
+    }
+
+    // WriteTo 会消耗 bufs, 使用副本以便复用底层数组
+    bufs := m.bufs
+    _, err := bufs.WriteTo(m.w)
+
+    // 不再引用已写入的数据
+    for i := range m.bufs {
+        m.bufs[i] = nil
+    }
+    m.bufs = m.bufs[:0]
+    return err
+}
+
+// ---------------- 定长长度头 ----------------
+
+type lengthCodec struct {
+    size  int
+    order binary.ByteOrder
+}
+
+func (m *lengthCodec) NewFrameReader(r io.Reader) FrameReader {
+    return &lengthFrameReader{codec: m, r: bufferedReader(r), head: make([]byte, m.size)}
+}
+
+func (m *lengthCodec) NewFrameWriter(w io.Writer) FrameWriter {
+    return &buffersFrameWriter{w: w, encode: m.encodeFrame}
+}
+
+// 长度头能表示的最大长度
+func (m *lengthCodec) maxLength() uint64 {
+    if m.size >= 8 {
+        return math.MaxUint64
+    }
+    return 1<<(uint(m.size)*8) - 1
+}
+
+func (m *lengthCodec) decode(head []byte) uint64 {
+    switch m.size {
+    case 2:
+        return uint64(m.order.Uint16(head))
+    case 4:
+        return uint64(m.order.Uint32(head))
+    }
+    return m.order.Uint64(head)
+}
+
+func (m *lengthCodec) encode(length uint64) []byte {
+    head := make([]byte, m.size)
+    switch m.size {
+    case 2:
+        m.order.PutUint16(head, uint16(length))
+    case 4:
+        m.order.PutUint32(head, uint32(length))
+    default:
+        m.order.PutUint64(head, length)
+    }
+    return head
+}
+
+type lengthFrameReader struct {
+    codec *lengthCodec
+    r     io.Reader
+    head  []byte
+}
+
+func (m *lengthFrameReader) ReadFrame() ([]byte, error) {
+    return m.readFrame(false)
+}
+
+func (m *lengthFrameReader) ReadPooledFrame() ([]byte, error) {
+    return m.readFrame(true)
+}
+
+func (m *lengthFrameReader) readFrame(pooled bool) ([]byte, error) {
+    if _, err := io.ReadFull(m.r, m.head); err != nil {
+        return nil, err
+    }
+
+    size := m.codec.decode(m.head)
+    if size == 0 {
+        return nil, nil
+    }
+    if size >= uint64(DataPackageBuffSize) {
+        return nil, frameTooLargeError(size)
+    }
+
+    if pooled {
+        return readFull(m.r, GetBuffer(int(size)), true)
+    }
+    return readFull(m.r, makeBuffer(int(size)), false)
+}
+
+func (m *lengthCodec) encodeFrame(bufs net.Buffers, parts [][]byte) (net.Buffers, error) {
+    length := uint64(partsLength(parts))
+    if length > m.maxLength() {
+        return bufs, &FrameTooLargeError{Size: length, Limit: m.maxLength()}
+    }
+
+    bufs = append(bufs, m.encode(length))
+    return appendParts(bufs, parts), nil
+}
+
+// ---------------- uvarint 长度头 ----------------
+
+type uvarintCodec struct{}
+
+func (uvarintCodec) NewFrameReader(r io.Reader) FrameReader {
+    return &uvarintFrameReader{r: bufferedReader(r)}
+}
+
+func (m uvarintCodec) NewFrameWriter(w io.Writer) FrameWriter {
+    return &buffersFrameWriter{w: w, encode: m.encodeFrame}
+}
+
+type uvarintFrameReader struct {
+    r *bufio.Reader
+}
+
+func (m *uvarintFrameReader) ReadFrame() ([]byte, error) {
+    return m.readFrame(false)
+}
+
+func (m *uvarintFrameReader) ReadPooledFrame() ([]byte, error) {
+    return m.readFrame(true)
+}
+
+func (m *uvarintFrameReader) readFrame(pooled bool) ([]byte, error) {
+    size, err := binary.ReadUvarint(m.r)
+    if err != nil {
+        return nil, err
+    }
+
+    if size == 0 {
+        return nil, nil
+    }
+    if size >= uint64(DataPackageBuffSize) {
+        return nil, frameTooLargeError(size)
+    }
+
+    if pooled {
+        return readFull(m.r, GetBuffer(int(size)), true)
+    }
+    return readFull(m.r, makeBuffer(int(size)), false)
+}
+
+func (uvarintCodec) encodeFrame(bufs net.Buffers, parts [][]byte) (net.Buffers, error) {
+    head := make([]byte, binary.MaxVarintLen64)
+    n := binary.PutUvarint(head, uint64(partsLength(parts)))
+
+    bufs = append(bufs, head[:n])
+    return appendParts(bufs, parts), nil
+}
+
+// ---------------- 分隔符 ----------------
+
+// 转义后的字节为原字节异或该值, 保证转义后不会出现分隔符
+const delimiterEscapeMask = 0x20
+
+type delimiterCodec struct {
+    delim byte
+    // 转义字节
+    esc byte
+}
+
+func (m delimiterCodec) NewFrameReader(r io.Reader) FrameReader {
+    return &delimiterFrameReader{delim: m.delim, esc: m.esc, r: bufferedReader(r)}
+}
+
+func (m delimiterCodec) NewFrameWriter(w io.Writer) FrameWriter {
+    return &buffersFrameWriter{w: w, encode: m.encodeFrame}
+}
+
+type delimiterFrameReader struct {
+    delim byte
+    esc   byte
+    r     *bufio.Reader
+}
+
+func (m *delimiterFrameReader) ReadFrame() ([]byte, error) {
+    return m.readFrame(makeBuffer)
+}
+
+func (m *delimiterFrameReader) ReadPooledFrame() ([]byte, error) {
+    return m.readFrame(GetBuffer)
+}
+
+func (m *delimiterFrameReader) readFrame(alloc func(size int) []byte) ([]byte, error) {
+    // 大部分情况下一个帧可以完整的放在 bufio 的缓冲中, 这时只需要拷贝一次
+    line, err := m.r.ReadSlice(m.delim)
+    var frame []byte
+    for err == bufio.ErrBufferFull {
+        frame = append(frame, line...)
+        if len(frame) >= DataPackageBuffSize {
+            return nil, frameTooLargeError(uint64(len(frame)))
+        }
+        line, err = m.r.ReadSlice(m.delim)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if frame != nil {
+        line = append(frame, line...)
+    }
+    line = line[:len(line)-1]
+    if len(line) == 0 {
+        return nil, nil
+    }
+    if len(line) >= DataPackageBuffSize {
+        return nil, frameTooLargeError(uint64(len(line)))
+    }
+
+    escaped := bytes.Count(line, []byte{m.esc})
+    if escaped == 0 {
+        buff := alloc(len(line))
+        copy(buff, line)
+        return buff, nil
+    }
+    if line[len(line)-1] == m.esc {
+        return nil, fmt.Errorf("%w: 转义字节后没有数据", ErrInvalidFrame)
+    }
+
+    // 去掉转义
+    buff := alloc(len(line) - escaped)
+    n := 0
+    for i := 0; i < len(line); i++ {
+        b := line[i]
+        if b == m.esc {
+            i++
+            b = line[i] ^ delimiterEscapeMask
+        }
+        buff[n] = b
+        n++
+    }
+    return buff, nil
+}
+
+func (m delimiterCodec) encodeFrame(bufs net.Buffers, parts [][]byte) (net.Buffers, error) {
+    for _, p := range parts {
+        if len(p) == 0 {
+            continue
+        }
+        // 不需要转义时直接使用原数据
+        if bytes.IndexByte(p, m.delim) == -1 && bytes.IndexByte(p, m.esc) == -1 {
+            bufs = append(bufs, p)
+            continue
+        }
+        bufs = append(bufs, m.escape(p))
+    }
+    return append(bufs, []byte{m.delim}), nil
+}
+
+func (m delimiterCodec) escape(p []byte) []byte {
+    out := make([]byte, 0, len(p)+len(p)/8+1)
+    for _, b := range p {
+        if b == m.delim || b == m.esc {
+            out = append(out, m.esc, b^delimiterEscapeMask)
+            continue
+        }
+        out = append(out, b)
+    }
+    return out
+}
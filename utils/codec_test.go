@@ -0,0 +1,248 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "bufio"
+    "encoding/binary"
+    "io"
+    "io/ioutil"
+    "net"
+    "testing"
+)
+
+// 建立一个本地 tcp 连接, 对端丢弃所有数据
+func discardConn(b *testing.B) net.Conn {
+    b.Helper()
+
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        b.Fatal(err)
+    }
+    defer listener.Close()
+
+    go func() {
+        conn, err := listener.Accept()
+        if err != nil {
+            return
+        }
+        _, _ = io.Copy(ioutil.Discard, conn)
+        _ = conn.Close()
+    }()
+
+    conn, err := net.Dial("tcp", listener.Addr().String())
+    if err != nil {
+        b.Fatal(err)
+    }
+    return conn
+}
+
+// 改为 net.Buffers 之前的写入方式, 帧头和数据分别调用一次 Write
+func writeFrameTwice(w io.Writer, head, data []byte) error {
+    binary.BigEndian.PutUint32(head, uint32(len(data)))
+    if _, err := w.Write(head); err != nil {
+        return err
+    }
+    _, err := w.Write(data)
+    return err
+}
+
+func benchmarkWrite(b *testing.B, size, batch int, buffers bool) {
+    conn := discardConn(b)
+    defer conn.Close()
+
+    data := make([]byte, size)
+    head := make([]byte, 4)
+    writer := Uint32BECodec.NewFrameWriter(conn)
+
+    b.SetBytes(int64(size * batch))
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        for j := 0; j < batch; j++ {
+            var err error
+            if buffers {
+                err = writer.BufferFrame(data)
+            } else {
+                err = writeFrameTwice(conn, head, data)
+            }
+            if err != nil {
+                b.Fatal(err)
+            }
+        }
+        if buffers {
+            if err := writer.Flush(); err != nil {
+                b.Fatal(err)
+            }
+        }
+    }
+}
+
+func BenchmarkWriteFrameTwoWrites(b *testing.B) {
+    benchmarkWrite(b, 128, 1, false)
+}
+
+func BenchmarkWriteFrameBuffers(b *testing.B) {
+    benchmarkWrite(b, 128, 1, true)
+}
+
+func BenchmarkWriteFrameBatch16TwoWrites(b *testing.B) {
+    benchmarkWrite(b, 128, 16, false)
+}
+
+func BenchmarkWriteFrameBatch16Buffers(b *testing.B) {
+    benchmarkWrite(b, 128, 16, true)
+}
+
+// 循环读取同一段数据的连接
+type loopConn struct {
+    net.Conn
+    data []byte
+    off  int
+}
+
+func (m *loopConn) Read(p []byte) (int, error) {
+    n := copy(p, m.data[m.off:])
+    m.off = (m.off + n) % len(m.data)
+    return n, nil
+}
+
+// 编码后的多个帧, 每个帧的数据长度为 size
+func encodedFrames(b *testing.B, codec Codec, size int) []byte {
+    var buf writeBuffer
+    writer := codec.NewFrameWriter(&buf)
+    data := make([]byte, size)
+    for i := 0; i < 64; i++ {
+        if err := writer.WriteFrame(data); err != nil {
+            b.Fatal(err)
+        }
+    }
+    return buf
+}
+
+type writeBuffer []byte
+
+func (m *writeBuffer) Write(p []byte) (int, error) {
+    *m = append(*m, p...)
+    return len(p), nil
+}
+
+func benchmarkRead(b *testing.B, size int, read func(conn net.Conn) func() ([]byte, error), pooled bool) {
+    conn := &loopConn{data: encodedFrames(b, Uint32BECodec, size)}
+    readFrame := read(conn)
+
+    b.SetBytes(int64(size))
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        data, err := readFrame()
+        if err != nil || len(data) != size {
+            b.Fatal(len(data), err)
+        }
+        if pooled {
+            PutBuffer(data)
+        }
+    }
+}
+
+// 改为帧读取器之前的 WaitConnData, 每次读取都分配新的缓冲
+func oldWaitConnData(conn io.Reader, length int) ([]byte, error) {
+    fullbuff := make([]byte, length)
+
+    var index int
+    var size int
+    for index < length {
+        size = length - index
+        if size > DataBuffSize {
+            size = DataBuffSize
+        }
+
+        buff := fullbuff[index : index+size]
+        le, err := conn.Read(buff)
+        index += le
+
+        if err != nil {
+            return fullbuff[:index], err
+        }
+    }
+    return fullbuff, nil
+}
+
+// 改为帧读取器之前的 WaitConnFullData, 不使用 bufio, 数据头和数据各分配一次
+func oldWaitConnFullData(conn io.Reader) ([]byte, error) {
+    dataHeader, err := oldWaitConnData(conn, 4)
+    if err != nil {
+        return dataHeader, err
+    }
+
+    dataSize := int(binary.BigEndian.Uint32(dataHeader))
+    if dataSize == 0 {
+        return dataHeader, nil
+    }
+    if dataSize >= DataPackageBuffSize {
+        return dataHeader, frameTooLargeError(uint64(dataSize))
+    }
+
+    return oldWaitConnData(conn, dataSize)
+}
+
+func readOldWaitConnFullData(conn net.Conn) func() ([]byte, error) {
+    return func() ([]byte, error) {
+        return oldWaitConnFullData(conn)
+    }
+}
+
+func readFrame(conn net.Conn) func() ([]byte, error) {
+    return Uint32BECodec.NewFrameReader(conn).ReadFrame
+}
+
+func readPooledFrame(conn net.Conn) func() ([]byte, error) {
+    return Uint32BECodec.NewFrameReader(conn).ReadPooledFrame
+}
+
+func BenchmarkReadSmallOldWaitConnFullData(b *testing.B) {
+    benchmarkRead(b, 128, readOldWaitConnFullData, false)
+}
+
+func BenchmarkReadSmallFrame(b *testing.B) {
+    benchmarkRead(b, 128, readFrame, false)
+}
+
+func BenchmarkReadSmallPooledFrame(b *testing.B) {
+    benchmarkRead(b, 128, readPooledFrame, true)
+}
+
+func BenchmarkReadLargeOldWaitConnFullData(b *testing.B) {
+    benchmarkRead(b, 64*1024, readOldWaitConnFullData, false)
+}
+
+func BenchmarkReadLargeFrame(b *testing.B) {
+    benchmarkRead(b, 64*1024, readFrame, false)
+}
+
+func BenchmarkReadLargePooledFrame(b *testing.B) {
+    benchmarkRead(b, 64*1024, readPooledFrame, true)
+}
+
+// 每个连接创建帧读取器的内存占用
+func BenchmarkNewFrameReader(b *testing.B) {
+    var conn net.Conn = &loopConn{}
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        _ = Uint32BECodec.NewFrameReader(conn)
+    }
+}
+
+func BenchmarkNewFrameReaderDataBuffSize(b *testing.B) {
+    var conn net.Conn = &loopConn{}
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        _ = Uint32BECodec.NewFrameReader(bufio.NewReaderSize(conn, DataBuffSize))
+    }
+}
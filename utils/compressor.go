@@ -0,0 +1,117 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "bytes"
+    "compress/flate"
+    "compress/gzip"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "sync"
+)
+
+// 压缩器, 必须并发安全
+type Compressor interface {
+    // 名称, 用于在信任阶段协商, 不能包含逗号
+    Name() string
+    Compress(data []byte) ([]byte, error)
+    Decompress(data []byte) ([]byte, error)
+}
+
+var (
+    // 默认压缩级别的 gzip
+    GzipCompressor = NewGzipCompressor(gzip.DefaultCompression)
+    // 默认压缩级别的 deflate
+    DeflateCompressor = NewDeflateCompressor(flate.DefaultCompression)
+)
+
+type compressWriter interface {
+    io.WriteCloser
+    Reset(w io.Writer)
+}
+
+type streamCompressor struct {
+    name      string
+    writers   sync.Pool
+    newReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+func NewGzipCompressor(level int) Compressor {
+    return &streamCompressor{
+        name: "gzip",
+        writers: sync.Pool{New: func() interface{} {
+            w, err := gzip.NewWriterLevel(nil, level)
+            if err != nil {
+                return nil
+            }
+            return w
+        }},
+        newReader: func(r io.Reader) (io.ReadCloser, error) {
+            return gzip.NewReader(r)
+        },
+    }
+}
+
+func NewDeflateCompressor(level int) Compressor {
+    return &streamCompressor{
+        name: "deflate",
+        writers: sync.Pool{New: func() interface{} {
+            w, err := flate.NewWriter(nil, level)
+            if err != nil {
+                return nil
+            }
+            return w
+        }},
+        newReader: func(r io.Reader) (io.ReadCloser, error) {
+            return flate.NewReader(r), nil
+        },
+    }
+}
+
+func (m *streamCompressor) Name() string {
+    return m.name
+}
+
+func (m *streamCompressor) Compress(data []byte) ([]byte, error) {
+    w, ok := m.writers.Get().(compressWriter)
+    if !ok {
+        return nil, fmt.Errorf("%w: %s", ErrInvalidCompressLevel, m.name)
+    }
+    defer m.writers.Put(w)
+
+    var buff bytes.Buffer
+    w.Reset(&buff)
+    if _, err := w.Write(data); err != nil {
+        return nil, err
+    }
+    if err := w.Close(); err != nil {
+        return nil, err
+    }
+    return buff.Bytes(), nil
+}
+
+// 解压后的数据不能超过 DataPackageBuffSize
+func (m *streamCompressor) Decompress(data []byte) ([]byte, error) {
+    r, err := m.newReader(bytes.NewReader(data))
+    if err != nil {
+        return nil, err
+    }
+    defer r.Close()
+
+    out, err := ioutil.ReadAll(io.LimitReader(r, int64(DataPackageBuffSize)))
+    if err != nil {
+        return nil, err
+    }
+    if len(out) >= DataPackageBuffSize {
+        return nil, frameTooLargeError(uint64(len(out)))
+    }
+    return out, nil
+}
@@ -0,0 +1,23 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "syscall"
+    "time"
+)
+
+// 进程已使用的 cpu 时间
+func processCPUTime() time.Duration {
+    var usage syscall.Rusage
+    if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+        return 0
+    }
+    return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "time"
+)
+
+// 不支持的平台上不统计 cpu 时间
+func processCPUTime() time.Duration {
+    return 0
+}
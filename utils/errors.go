@@ -0,0 +1,64 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "errors"
+    "fmt"
+)
+
+var (
+    // 数据长度超过限制, 具体信息见 FrameTooLargeError
+    ErrFrameTooLarge = errors.New("数据长度超过限制")
+    // 帧数据格式错误
+    ErrInvalidFrame = errors.New("无效的帧数据")
+    // 握手消息格式错误
+    ErrInvalidHandshake = errors.New("无效的握手消息")
+    // 无效的压缩级别
+    ErrInvalidCompressLevel = errors.New("无效的压缩级别")
+)
+
+// 数据长度超过限制, 可以用 errors.Is(err, ErrFrameTooLarge) 判断
+type FrameTooLargeError struct {
+    // 数据长度
+    Size uint64
+    // 允许的最大长度
+    Limit uint64
+}
+
+func (m *FrameTooLargeError) Error() string {
+    return fmt.Sprintf("数据长度 %d 超过 %d bytes", m.Size, m.Limit)
+}
+
+func (m *FrameTooLargeError) Is(target error) bool {
+    return target == ErrFrameTooLarge
+}
+
+// 协议错误, 包装了读写连接时的底层错误(通常是 net 错误)或者协议相关的错误
+type ProtocolError struct {
+    // 出错的操作, 如 dial, read, write, handshake
+    Op  string
+    Err error
+}
+
+func (m *ProtocolError) Error() string {
+    return m.Op + ": " + m.Err.Error()
+}
+
+func (m *ProtocolError) Unwrap() error {
+    return m.Err
+}
+
+// 包装为协议错误, err 为 nil 时返回 nil
+func NewProtocolError(op string, err error) error {
+    if err == nil {
+        return nil
+    }
+    return &ProtocolError{Op: op, Err: err}
+}
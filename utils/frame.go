@@ -0,0 +1,85 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "fmt"
+    "github.com/zlyuancn/ztcp/config"
+    "math"
+)
+
+// 生成帧头(帧类型, 扩展头), 之后应该紧跟数据
+func FrameHead(frameType config.FrameType, ext []byte) []byte {
+    head := make([]byte, 0, config.DataFrameTypeLength+len(ext))
+    head = append(head, byte(frameType))
+    head = append(head, ext...)
+    return head
+}
+
+// 解析帧, 返回帧类型和之后的数据
+func ParseFrame(body []byte) (config.FrameType, []byte, error) {
+    if len(body) < config.DataFrameTypeLength {
+        return 0, nil, fmt.Errorf("%w: 帧数据不完整", ErrInvalidFrame)
+    }
+    return config.FrameType(body[0]), body[config.DataFrameTypeLength:], nil
+}
+
+// 解析请求和响应帧, 返回请求id和之后的数据
+func ParseCallFrame(body []byte) (uint64, []byte, error) {
+    if len(body) < config.DataRequestIdLength {
+        return 0, nil, fmt.Errorf("%w: 请求帧数据不完整", ErrInvalidFrame)
+    }
+    return BytesToUint64(body), body[config.DataRequestIdLength:], nil
+}
+
+// 生成错误响应帧的扩展头(请求id, 错误码)
+func ErrorResponseExt(requestId uint64, code config.CallErrorCode) []byte {
+    return append(Uint64ToBytes(requestId), byte(code))
+}
+
+// 解析错误响应帧请求id之后的数据, 返回错误码和错误信息
+func ParseErrorResponse(body []byte) (config.CallErrorCode, []byte, error) {
+    if len(body) < config.DataErrorCodeLength {
+        return 0, nil, fmt.Errorf("%w: 错误响应帧数据不完整", ErrInvalidFrame)
+    }
+    return config.CallErrorCode(body[0]), body[config.DataErrorCodeLength:], nil
+}
+
+// 生成路由帧的扩展头(路由长度, 路由)
+func RouteExt(route string) ([]byte, error) {
+    if len(route) > math.MaxUint16 {
+        return nil, &FrameTooLargeError{Size: uint64(len(route)), Limit: math.MaxUint16}
+    }
+
+    ext := make([]byte, config.DataRouteLength, config.DataRouteLength+len(route))
+    ext[0], ext[1] = byte(len(route)>>8), byte(len(route))
+    return append(ext, route...), nil
+}
+
+// 解析路由帧, 返回路由和之后的数据
+func ParseRouteFrame(body []byte) (string, []byte, error) {
+    if len(body) < config.DataRouteLength {
+        return "", nil, fmt.Errorf("%w: 路由帧数据不完整", ErrInvalidFrame)
+    }
+
+    length := int(body[0])<<8 | int(body[1])
+    body = body[config.DataRouteLength:]
+    if len(body) < length {
+        return "", nil, fmt.Errorf("%w: 路由帧数据不完整", ErrInvalidFrame)
+    }
+    return string(body[:length]), body[length:], nil
+}
+
+// 解析 ping 和 pong 帧, 返回时间戳
+func ParsePingFrame(body []byte) (uint64, error) {
+    if len(body) < config.DataTimestampLength {
+        return 0, fmt.Errorf("%w: ping 帧数据不完整", ErrInvalidFrame)
+    }
+    return BytesToUint64(body), nil
+}
@@ -0,0 +1,41 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "io"
+    "math"
+)
+
+// 写入一个握手消息, 握手消息使用2字节大端长度头, 不受 Codec 影响
+func WriteHandshakeMsg(w io.Writer, msg []byte) error {
+    if len(msg) > math.MaxUint16 {
+        return &FrameTooLargeError{Size: uint64(len(msg)), Limit: math.MaxUint16}
+    }
+
+    buff := make([]byte, 2+len(msg))
+    buff[0], buff[1] = byte(len(msg)>>8), byte(len(msg))
+    copy(buff[2:], msg)
+    _, err := w.Write(buff)
+    return err
+}
+
+// 读取一个握手消息
+func ReadHandshakeMsg(r io.Reader) ([]byte, error) {
+    head, err := WaitConnData(r, 2)
+    if err != nil {
+        return nil, err
+    }
+
+    length := int(head[0])<<8 | int(head[1])
+    if length == 0 {
+        return []byte{}, nil
+    }
+    return WaitConnData(r, length)
+}
@@ -16,41 +16,60 @@ import (
 type heartbeatEvent func(timer *HeartbeatTime)
 
 //心跳时间类型,不应该用值类型去做拷贝，而应该用指针
+//
+//使用精度为 precision 的共享时间轮, 不再为每个连接创建协程
 type HeartbeatTime struct {
     isrun int32
-    reset int32
+    // 最后一次活动时时间轮的刻度
+    last int64
+
+    target   time.Duration
+    callback heartbeatEvent
+    wheel    *TimingWheel
+    timer    *WheelTimer
 }
 
 func NewHeartbeatTime(target time.Duration, precision time.Duration, callback heartbeatEvent) *HeartbeatTime {
+    wheel := SharedTimingWheel(precision)
     timer := &HeartbeatTime{
-        isrun: 1,
+        isrun:    1,
+        last:     wheel.Ticks(),
+        target:   target,
+        callback: callback,
+        wheel:    wheel,
     }
-    go func() {
-        var clock time.Duration
-        for timer.IsRun() {
-            time.Sleep(precision)
-
-            // 要求重置计时
-            if atomic.CompareAndSwapInt32(&timer.reset, 1, 0) {
-                clock = 0
-            }
-
-            clock += precision
-            if clock >= target {
-                clock -= target
-                callback(timer)
-            }
-        }
-    }()
+    timer.timer = wheel.NewTimer(timer.expire)
+    timer.timer.Reset(target)
     return timer
 }
 
+// 定时器到期, 期间有活动时只是顺延, 不触发回调
+//
+// 使用 Rearm 重新启动定时器, 和 Stop 并发时不会在 Stop 之后重新启动
+func (m *HeartbeatTime) expire() {
+    if !m.IsRun() {
+        return
+    }
+
+    idle := time.Duration(m.wheel.Ticks()-atomic.LoadInt64(&m.last)) * m.wheel.Tick()
+    if idle < m.target {
+        m.timer.Rearm(m.target - idle)
+        return
+    }
+
+    atomic.StoreInt64(&m.last, m.wheel.Ticks())
+    m.callback(m)
+    m.timer.Rearm(m.target)
+}
+
+// 记录一次活动, 只是一次原子写入
 func (m *HeartbeatTime) RefHeartbeat() {
-    atomic.StoreInt32(&m.reset, 1)
+    atomic.StoreInt64(&m.last, m.wheel.Ticks())
 }
 
 func (m *HeartbeatTime) Stop() {
     atomic.StoreInt32(&m.isrun, 0)
+    m.timer.Stop()
 }
 
 func (m *HeartbeatTime) IsRun() bool {
@@ -0,0 +1,289 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "runtime"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func (m *WheelTimer) active() bool {
+    m.wheel.mx.Lock()
+    defer m.wheel.mx.Unlock()
+    return m.slot != -1
+}
+
+// 大量心跳共享一个时间轮, 不会为每个心跳创建协程
+func TestHeartbeatTimeGoroutines(t *testing.T) {
+    const n = 10000
+
+    before := runtime.NumGoroutine()
+    timers := make([]*HeartbeatTime, n)
+    for i := range timers {
+        timers[i] = NewHeartbeatTime(time.Minute, 100*time.Millisecond, func(timer *HeartbeatTime) {})
+    }
+    after := runtime.NumGoroutine()
+
+    for _, timer := range timers {
+        timer.Stop()
+    }
+
+    // 只有时间轮自己的协程
+    if after-before > 2 {
+        t.Fatalf("创建 %d 个心跳后协程数量从 %d 增加到了 %d", n, before, after)
+    }
+}
+
+// Rearm 不会撤销之前的 Stop, Reset 会重新启动
+func TestWheelTimerRearmAfterStop(t *testing.T) {
+    wheel := NewTimingWheel(time.Second, 8)
+    timer := wheel.NewTimer(func() {})
+
+    if !timer.Rearm(time.Minute) || !timer.active() {
+        t.Fatal("没有调用过 Stop 时 Rearm 应该启动定时器")
+    }
+    timer.Stop()
+    if timer.Rearm(time.Minute) || timer.active() {
+        t.Fatal("Stop 之后 Rearm 不应该启动定时器")
+    }
+
+    timer.Reset(time.Minute)
+    if !timer.active() {
+        t.Fatal("Reset 应该启动定时器")
+    }
+    if !timer.Rearm(time.Minute) || !timer.active() {
+        t.Fatal("Reset 之后 Rearm 应该启动定时器")
+    }
+    timer.Stop()
+}
+
+// 定时器到期后重新启动和并发的 Stop 竞争, Stop 之后定时器不能被重新启动
+func TestHeartbeatTimeStopRace(t *testing.T) {
+    const n = 200
+
+    var fired int32
+    timers := make([]*HeartbeatTime, n)
+    for i := range timers {
+        timers[i] = NewHeartbeatTime(2*time.Millisecond, time.Millisecond, func(timer *HeartbeatTime) {
+            atomic.AddInt32(&fired, 1)
+        })
+    }
+
+    // 一部分有活动, 到期时走顺延的路径, 另一部分到期时走回调的路径
+    done := make(chan struct{})
+    var wg sync.WaitGroup
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        for {
+            select {
+            case <-done:
+                return
+            default:
+            }
+            for i := 0; i < n; i += 2 {
+                timers[i].RefHeartbeat()
+            }
+            runtime.Gosched()
+        }
+    }()
+
+    for _, timer := range timers {
+        time.Sleep(50 * time.Microsecond)
+        timer.Stop()
+    }
+    close(done)
+    wg.Wait()
+
+    // 等待正在执行的回调结束
+    time.Sleep(20 * time.Millisecond)
+    for i, timer := range timers {
+        if timer.timer.active() {
+            t.Fatalf("第 %d 个心跳在 Stop 之后被重新启动", i)
+        }
+    }
+
+    count := atomic.LoadInt32(&fired)
+    time.Sleep(20 * time.Millisecond)
+    if atomic.LoadInt32(&fired) != count {
+        t.Fatal("Stop 之后回调仍然被调用")
+    }
+}
+
+func BenchmarkRefHeartbeat(b *testing.B) {
+    timer := NewHeartbeatTime(time.Minute, time.Second, func(timer *HeartbeatTime) {})
+    defer timer.Stop()
+
+    b.ReportAllocs()
+    b.RunParallel(func(pb *testing.PB) {
+        for pb.Next() {
+            timer.RefHeartbeat()
+        }
+    })
+}
+
+// 时间轮中已有大量定时器时重置一个定时器的开销
+func BenchmarkWheelTimerReset(b *testing.B) {
+    wheel := NewTimingWheel(time.Second, 512)
+    timers := make([]*WheelTimer, 10000)
+    for i := range timers {
+        timers[i] = wheel.AfterFunc(time.Duration(i)*time.Second, func() {})
+    }
+    defer func() {
+        for _, timer := range timers {
+            timer.Stop()
+        }
+    }()
+
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        timers[i%len(timers)].Reset(time.Minute)
+    }
+}
+
+func BenchmarkNewHeartbeatTime(b *testing.B) {
+    timers := make([]*HeartbeatTime, 0, b.N)
+    defer func() {
+        for _, timer := range timers {
+            timer.Stop()
+        }
+    }()
+
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        timers = append(timers, NewHeartbeatTime(time.Minute, time.Second, func(timer *HeartbeatTime) {}))
+    }
+}
+
+// 改用时间轮之前的心跳实现, 每个心跳一个 sleep 循环的协程, 用于对比
+type sleepHeartbeatTime struct {
+    isrun int32
+    reset int32
+}
+
+func newSleepHeartbeatTime(target time.Duration, precision time.Duration, callback func(timer *sleepHeartbeatTime)) *sleepHeartbeatTime {
+    timer := &sleepHeartbeatTime{
+        isrun: 1,
+    }
+    go func() {
+        var clock time.Duration
+        for timer.IsRun() {
+            time.Sleep(precision)
+
+            // 要求重置计时
+            if atomic.CompareAndSwapInt32(&timer.reset, 1, 0) {
+                clock = 0
+            }
+
+            clock += precision
+            if clock >= target {
+                clock -= target
+                callback(timer)
+            }
+        }
+    }()
+    return timer
+}
+
+func (m *sleepHeartbeatTime) RefHeartbeat() {
+    atomic.StoreInt32(&m.reset, 1)
+}
+
+func (m *sleepHeartbeatTime) Stop() {
+    atomic.StoreInt32(&m.isrun, 0)
+}
+
+func (m *sleepHeartbeatTime) IsRun() bool {
+    return atomic.LoadInt32(&m.isrun) == 1
+}
+
+type benchHeartbeat interface {
+    RefHeartbeat()
+    Stop()
+}
+
+const (
+    benchHeartbeatTarget    = time.Minute
+    benchHeartbeatPrecision = 10 * time.Millisecond
+    // 每次操作中心跳运行的时间
+    benchHeartbeatWindow = 200 * time.Millisecond
+)
+
+// 每次操作创建 n 个心跳, 在 benchHeartbeatWindow 内持续刷新后全部停止
+//
+// 额外报告心跳运行时增加的协程数量和每次操作使用的 cpu 时间
+func benchmarkHeartbeats(b *testing.B, n int, newTimer func() benchHeartbeat) {
+    before := runtime.NumGoroutine()
+    timers := make([]benchHeartbeat, n)
+    var goroutines int
+    var cpu time.Duration
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        start := processCPUTime()
+        for j := range timers {
+            timers[j] = newTimer()
+        }
+        if delta := runtime.NumGoroutine() - before; delta > goroutines {
+            goroutines = delta
+        }
+
+        // 模拟连接上一直有数据, 心跳不会超时
+        for deadline := time.Now().Add(benchHeartbeatWindow); time.Now().Before(deadline); {
+            time.Sleep(benchHeartbeatPrecision)
+            for _, timer := range timers {
+                timer.RefHeartbeat()
+            }
+        }
+
+        for _, timer := range timers {
+            timer.Stop()
+        }
+        cpu += processCPUTime() - start
+
+        // 等待心跳的协程退出, 不影响下一次操作
+        b.StopTimer()
+        for runtime.NumGoroutine() > before {
+            time.Sleep(benchHeartbeatPrecision)
+        }
+        b.StartTimer()
+    }
+    b.StopTimer()
+
+    b.ReportMetric(float64(goroutines), "goroutines")
+    b.ReportMetric(float64(cpu)/float64(b.N), "cpu-ns/op")
+}
+
+func newBenchSleepHeartbeat() benchHeartbeat {
+    return newSleepHeartbeatTime(benchHeartbeatTarget, benchHeartbeatPrecision, func(timer *sleepHeartbeatTime) {})
+}
+
+func newBenchWheelHeartbeat() benchHeartbeat {
+    return NewHeartbeatTime(benchHeartbeatTarget, benchHeartbeatPrecision, func(timer *HeartbeatTime) {})
+}
+
+func BenchmarkHeartbeatSleepLoop10k(b *testing.B) {
+    benchmarkHeartbeats(b, 10000, newBenchSleepHeartbeat)
+}
+
+func BenchmarkHeartbeatWheel10k(b *testing.B) {
+    benchmarkHeartbeats(b, 10000, newBenchWheelHeartbeat)
+}
+
+func BenchmarkHeartbeatSleepLoop100k(b *testing.B) {
+    benchmarkHeartbeats(b, 100000, newBenchSleepHeartbeat)
+}
+
+func BenchmarkHeartbeatWheel100k(b *testing.B) {
+    benchmarkHeartbeats(b, 100000, newBenchWheelHeartbeat)
+}
@@ -0,0 +1,57 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "sync"
+    "time"
+)
+
+// 令牌桶限速器
+type RateLimiter struct {
+    mx sync.Mutex
+    // 每秒生成的令牌数
+    rate float64
+    // 桶容量
+    burst  float64
+    tokens float64
+    last   time.Time
+}
+
+// 创建一个令牌桶限速器, 每秒生成 rate 个令牌, 最多积累 burst 个令牌
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+    if burst < 1 {
+        burst = 1
+    }
+    return &RateLimiter{
+        rate:   rate,
+        burst:  float64(burst),
+        tokens: float64(burst),
+        last:   time.Now(),
+    }
+}
+
+// 尝试获取一个令牌, 没有令牌时返回 false
+func (m *RateLimiter) Allow() bool {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    now := time.Now()
+    m.tokens += now.Sub(m.last).Seconds() * m.rate
+    if m.tokens > m.burst {
+        m.tokens = m.burst
+    }
+    m.last = now
+
+    if m.tokens < 1 {
+        return false
+    }
+    m.tokens--
+    return true
+}
@@ -0,0 +1,54 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "bytes"
+    "encoding/gob"
+    "encoding/json"
+)
+
+// 序列化器, 必须并发安全
+type Serializer interface {
+    Marshal(v interface{}) ([]byte, error)
+    Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+    JSONSerializer Serializer = jsonSerializer{}
+    // 每个数据都是独立编码的, 会包含完整的类型信息
+    GobSerializer Serializer = gobSerializer{}
+)
+
+// 默认序列化器
+var DefaultSerializer = JSONSerializer
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) {
+    return json.Marshal(v)
+}
+
+func (jsonSerializer) Unmarshal(data []byte, v interface{}) error {
+    return json.Unmarshal(data, v)
+}
+
+type gobSerializer struct{}
+
+func (gobSerializer) Marshal(v interface{}) ([]byte, error) {
+    var buff bytes.Buffer
+    if err := gob.NewEncoder(&buff).Encode(v); err != nil {
+        return nil, err
+    }
+    return buff.Bytes(), nil
+}
+
+func (gobSerializer) Unmarshal(data []byte, v interface{}) error {
+    return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,225 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/18
+   Description :
+-------------------------------------------------
+*/
+
+package utils
+
+import (
+    "github.com/zlyuancn/ztcp/config"
+    "sync"
+    "sync/atomic"
+    "time"
+)
+
+// 时间轮, 所有定时器共享一个协程, 添加, 重置和停止定时器都是 O(1) 的
+//
+// 只有存在定时器时才会运行协程, 定时器的精度为 tick
+type TimingWheel struct {
+    tick time.Duration
+    // 已经走过的刻度数
+    ticks int64
+
+    mx    sync.Mutex
+    slots []*WheelTimer
+    pos   int
+    count int
+    // 是否有协程在运行
+    running bool
+}
+
+// 创建时间轮(刻度间隔, 槽数量)
+func NewTimingWheel(tick time.Duration, slots int) *TimingWheel {
+    if tick <= 0 {
+        tick = config.DefaultHeartbeatPrecision
+    }
+    if slots < 1 {
+        slots = config.DefaultTimingWheelSlots
+    }
+    return &TimingWheel{
+        tick:  tick,
+        slots: make([]*WheelTimer, slots),
+    }
+}
+
+var sharedWheels = struct {
+    mx     sync.Mutex
+    wheels map[time.Duration]*TimingWheel
+}{wheels: make(map[time.Duration]*TimingWheel)}
+
+// 获取指定精度的共享时间轮
+func SharedTimingWheel(tick time.Duration) *TimingWheel {
+    sharedWheels.mx.Lock()
+    defer sharedWheels.mx.Unlock()
+
+    wheel, ok := sharedWheels.wheels[tick]
+    if !ok {
+        wheel = NewTimingWheel(tick, config.DefaultTimingWheelSlots)
+        sharedWheels.wheels[tick] = wheel
+    }
+    return wheel
+}
+
+// 在 d 之后在新的协程中执行 fn, 返回的定时器可以重置和停止
+func AfterFunc(d time.Duration, fn func()) *WheelTimer {
+    return SharedTimingWheel(config.DefaultHeartbeatPrecision).AfterFunc(d, fn)
+}
+
+func (m *TimingWheel) Tick() time.Duration {
+    return m.tick
+}
+
+// 获取已经走过的刻度数, 可以作为粗略的时钟
+func (m *TimingWheel) Ticks() int64 {
+    return atomic.LoadInt64(&m.ticks)
+}
+
+// 创建一个未启动的定时器, 调用 Reset 后启动
+func (m *TimingWheel) NewTimer(fn func()) *WheelTimer {
+    return &WheelTimer{wheel: m, fn: fn, slot: -1}
+}
+
+// 在 d 之后在新的协程中执行 fn
+func (m *TimingWheel) AfterFunc(d time.Duration, fn func()) *WheelTimer {
+    t := m.NewTimer(fn)
+    t.Reset(d)
+    return t
+}
+
+// 添加定时器, 调用者必须持有 mx
+func (m *TimingWheel) add(t *WheelTimer, d time.Duration) {
+    ticks := int((d + m.tick - 1) / m.tick)
+    if ticks < 1 {
+        ticks = 1
+    }
+    t.slot = (m.pos + ticks) % len(m.slots)
+    t.rounds = (ticks - 1) / len(m.slots)
+
+    t.prev = nil
+    t.next = m.slots[t.slot]
+    if t.next != nil {
+        t.next.prev = t
+    }
+    m.slots[t.slot] = t
+
+    m.count++
+    if !m.running {
+        m.running = true
+        go m.run()
+    }
+}
+
+// 移除定时器, 调用者必须持有 mx
+func (m *TimingWheel) remove(t *WheelTimer) {
+    if t.prev != nil {
+        t.prev.next = t.next
+    } else {
+        m.slots[t.slot] = t.next
+    }
+    if t.next != nil {
+        t.next.prev = t.prev
+    }
+    t.prev, t.next = nil, nil
+    t.slot = -1
+    m.count--
+}
+
+func (m *TimingWheel) run() {
+    ticker := time.NewTicker(m.tick)
+    defer ticker.Stop()
+
+    for range ticker.C {
+        if !m.advance() {
+            return
+        }
+    }
+}
+
+// 前进一个刻度并执行到期的定时器, 没有定时器时返回 false
+func (m *TimingWheel) advance() bool {
+    m.mx.Lock()
+    atomic.AddInt64(&m.ticks, 1)
+    m.pos = (m.pos + 1) % len(m.slots)
+
+    var expired []func()
+    for t := m.slots[m.pos]; t != nil; {
+        next := t.next
+        if t.rounds > 0 {
+            t.rounds--
+        } else {
+            m.remove(t)
+            expired = append(expired, t.fn)
+        }
+        t = next
+    }
+
+    running := m.count > 0
+    if !running {
+        m.running = false
+    }
+    m.mx.Unlock()
+
+    for _, fn := range expired {
+        go fn()
+    }
+    return running
+}
+
+// 时间轮定时器
+type WheelTimer struct {
+    wheel  *TimingWheel
+    fn     func()
+    slot   int
+    rounds int
+    prev   *WheelTimer
+    next   *WheelTimer
+    // 调用过 Stop 并且之后没有调用 Reset
+    stopped bool
+}
+
+// 重新设置定时器在 d 之后触发, 返回定时器之前是否在等待触发
+func (m *WheelTimer) Reset(d time.Duration) bool {
+    m.wheel.mx.Lock()
+    defer m.wheel.mx.Unlock()
+
+    m.stopped = false
+    active := m.slot != -1
+    if active {
+        m.wheel.remove(m)
+    }
+    m.wheel.add(m, d)
+    return active
+}
+
+// 和 Reset 相同, 但是调用过 Stop 之后不会重新启动, 返回是否启动了定时器
+//
+// 用于在定时器的回调中重新启动定时器, 检查和启动都在锁中进行, 不会撤销并发的 Stop
+func (m *WheelTimer) Rearm(d time.Duration) bool {
+    m.wheel.mx.Lock()
+    defer m.wheel.mx.Unlock()
+
+    if m.stopped {
+        return false
+    }
+    if m.slot != -1 {
+        m.wheel.remove(m)
+    }
+    m.wheel.add(m, d)
+    return true
+}
+
+// 停止定时器, 返回定时器之前是否在等待触发
+func (m *WheelTimer) Stop() bool {
+    m.wheel.mx.Lock()
+    defer m.wheel.mx.Unlock()
+
+    m.stopped = true
+    if m.slot == -1 {
+        return false
+    }
+    m.wheel.remove(m)
+    return true
+}
@@ -9,16 +9,16 @@
 package utils
 
 import (
-    "fmt"
-    "github.com/zlyuancn/zassert"
     "github.com/zlyuancn/ztcp/config"
+    "io"
     "net"
 )
 var DataBuffSize = config.DefaultDataBuffSize
+var ReadBuffSize = config.DefaultReadBuffSize
 var DataPackageBuffSize = config.DefaultDataPackageBuffSize
 
 // 等待一次指定长度的数据(已连接的conn, 数据总长度, 单次数据缓存大小)
-func WaitConnData(conn net.Conn, length int) ([] byte, error) {
+func WaitConnData(conn io.Reader, length int) ([] byte, error) {
     fullbuff := make([]byte, length)
 
     var index int
@@ -40,20 +40,11 @@ func WaitConnData(conn net.Conn, length int) ([] byte, error) {
     return fullbuff, nil
 }
 
-// 等待一个完整的数据
+// 等待一个使用4字节大端长度头的完整数据, 空数据(心跳)返回 nil
+//
+// 不使用缓冲, 不会多读取下一个数据
 func WaitConnFullData(conn net.Conn) ([] byte, error) {
-    dataHeader, err := WaitConnData(conn, config.DataHeaderLength)
-    if err != nil {
-        return dataHeader, err
-    }
-
-    dataSize := int(BytesToUint32(dataHeader))
-    if dataSize == 0 {
-        return dataHeader, nil
-    }
-    if dataSize >= DataPackageBuffSize {
-        return dataHeader, zassert.AssertError{fmt.Sprintf("数据长度超过设置的 %d bytes", DataPackageBuffSize)}
-    }
-
-    return WaitConnData(conn, dataSize)
+    codec := Uint32BECodec.(*lengthCodec)
+    reader := &lengthFrameReader{codec: codec, r: conn, head: make([]byte, codec.size)}
+    return reader.ReadFrame()
 }